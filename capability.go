@@ -0,0 +1,31 @@
+package vers
+
+import (
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/session"
+	"github.com/oomph-ac/mv/multiversion/capability"
+	"github.com/sandertv/gophertunnel/minecraft"
+)
+
+// Capabilities returns the capability.Set of the minecraft.Protocol passed. Protocols that do not publish their
+// capabilities, such as the latest protocol, are assumed to support all of them.
+func Capabilities(proto minecraft.Protocol) capability.Set {
+	if p, ok := proto.(interface{ Capabilities() capability.Set }); ok {
+		return p.Capabilities()
+	}
+	return capability.All
+}
+
+// Supports checks if the client at the other end of the session.Conn passed supports the capability.Capability
+// passed. False is returned if the protocol of the connection could not be found.
+func Supports(conn session.Conn, c capability.Capability) bool {
+	proto, ok := ConnProtocol(conn)
+	return ok && Capabilities(proto).Has(c)
+}
+
+// PlayerSupports checks if the client controlling the player passed supports the capability.Capability passed.
+// False is returned if the protocol of the player could not be found.
+func PlayerSupports(p *player.Player, c capability.Capability) bool {
+	proto, ok := PlayerProtocol(p)
+	return ok && Capabilities(proto).Has(c)
+}
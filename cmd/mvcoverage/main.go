@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/oomph-ac/mv/multiversion/mappings"
+	"github.com/oomph-ac/mv/multiversion/mv567"
+	"github.com/oomph-ac/mv/multiversion/mv568"
+	"github.com/oomph-ac/mv/multiversion/mv575"
+	"github.com/oomph-ac/mv/multiversion/mv582"
+	"github.com/oomph-ac/mv/multiversion/mv589"
+	"github.com/oomph-ac/mv/multiversion/mv594"
+	"github.com/oomph-ac/mv/multiversion/mv618"
+	"github.com/oomph-ac/mv/multiversion/mv622"
+	"github.com/oomph-ac/mv/multiversion/mv630"
+	"github.com/oomph-ac/mv/multiversion/mv649"
+	"github.com/oomph-ac/mv/multiversion/mv662"
+)
+
+// protocol is a protocol that holds block and item mappings.
+type protocol interface {
+	ID() int32
+	Ver() string
+	Mapping() mappings.MVMapping
+}
+
+// protocols holds all protocols whose coverage may be reported.
+var protocols = []protocol{
+	mv567.Protocol{},
+	mv568.Protocol{},
+	mv575.Protocol{},
+	mv582.Protocol{},
+	mv589.Protocol{},
+	mv594.Protocol{},
+	mv618.Protocol{},
+	mv622.Protocol{},
+	mv630.Protocol{},
+	mv649.Protocol{},
+	mv662.Protocol{},
+}
+
+// The following program reports the block states and items of the latest version that have no exact counterpart
+// in the mappings of the supported protocols, along with what each of them is translated to instead.
+//
+// Usage:
+//
+//	mvcoverage [-protocol <id>] [-summary]
+func main() {
+	id := flag.Int("protocol", 0, "only report the coverage of the protocol with this ID")
+	summary := flag.Bool("summary", false, "only print the number of block states and items without a counterpart")
+	flag.Parse()
+
+	found := false
+	for _, p := range protocols {
+		if *id != 0 && p.ID() != int32(*id) {
+			continue
+		}
+		found = true
+		report := mappings.Coverage(p.Mapping())
+		fmt.Printf("%v (%v): %v blocks, %v items without an exact counterpart\n", p.Ver(), p.ID(), len(report.Blocks), len(report.Items))
+		if *summary {
+			continue
+		}
+		for _, b := range report.Blocks {
+			fmt.Printf("\tblock %v -> %v\n", formatState(b.State.Name, b.State.Properties), formatState(b.Fallback.Name, b.Fallback.Properties))
+		}
+		for _, item := range report.Items {
+			fallback := item.Fallback
+			if fallback == "" {
+				fallback = "(none)"
+			}
+			fmt.Printf("\titem %v (%v) -> %v\n", item.Name, item.RuntimeID, fallback)
+		}
+	}
+	if !found {
+		fmt.Fprintf(os.Stderr, "unknown protocol %v\n", *id)
+		os.Exit(1)
+	}
+}
+
+// formatState formats a block state as its name followed by its properties, ordered by their keys.
+func formatState(name string, properties map[string]any) string {
+	keys := make([]string, 0, len(properties))
+	for k := range properties {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	values := make([]string, 0, len(keys))
+	for _, k := range keys {
+		values = append(values, fmt.Sprintf("%v=%v", k, properties[k]))
+	}
+	return fmt.Sprintf("%v[%v]", name, strings.Join(values, ","))
+}
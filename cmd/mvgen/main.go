@@ -0,0 +1,99 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// The following program generates the mapping data embedded in the multi-version packages from block palette and
+// item table dumps, such as those exported by a dedicated server, and scaffolds the packages of new versions.
+//
+// Usage:
+//
+//	mvgen mappings -blocks <palette> [-items <items>] [-state-version <version>] -out <dir>
+//	mvgen scaffold -protocol <id> -version <version> -blocks <palette> [-items <items>] [-dir <dir>]
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	var err error
+	switch os.Args[1] {
+	case "mappings":
+		err = runMappings(os.Args[2:])
+	case "scaffold":
+		err = runScaffold(os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// usage prints the usage of the program and exits.
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: mvgen <mappings|scaffold> [flags]")
+	os.Exit(2)
+}
+
+// inputFlags holds the flags describing the dumps that mapping data is generated from.
+type inputFlags struct {
+	blocks, items string
+	stateVersion  int
+}
+
+// register registers the flags in the flag.FlagSet passed.
+func (in *inputFlags) register(set *flag.FlagSet) {
+	set.StringVar(&in.blocks, "blocks", "", "path of the block palette dump, in NBT or JSON format")
+	set.StringVar(&in.items, "items", "", "path of the item table dump, in NBT or JSON format")
+	set.IntVar(&in.stateVersion, "state-version", 0, "version of block states in JSON dumps that have no version")
+}
+
+// runMappings runs the mappings command, which writes the block palette and item table passed to a directory.
+func runMappings(args []string) error {
+	var in inputFlags
+	set := flag.NewFlagSet("mappings", flag.ExitOnError)
+	in.register(set)
+	out := set.String("out", ".", "directory to write the mapping data to")
+	_ = set.Parse(args)
+
+	if in.blocks == "" && in.items == "" {
+		return fmt.Errorf("mappings: at least one of -blocks and -items must be passed")
+	}
+	_, err := writeMappings(in, *out)
+	return err
+}
+
+// runScaffold runs the scaffold command, which creates the package of a new version along with its mapping data.
+func runScaffold(args []string) error {
+	var in inputFlags
+	set := flag.NewFlagSet("scaffold", flag.ExitOnError)
+	in.register(set)
+	s := scaffold{}
+	set.IntVar(&s.Protocol, "protocol", 0, "protocol ID of the version")
+	set.StringVar(&s.Version, "version", "", "version, such as 1.20.80")
+	set.StringVar(&s.Module, "module", "github.com/oomph-ac/mv", "path of the module the package is created in")
+	dir := set.String("dir", "multiversion", "directory to create the package in")
+	_ = set.Parse(args)
+
+	if s.Protocol <= 0 || s.Version == "" || in.blocks == "" {
+		return fmt.Errorf("scaffold: -protocol, -version and -blocks must be passed")
+	}
+	pkg := filepath.Join(*dir, fmt.Sprintf("mv%v", s.Protocol))
+	if _, err := os.Stat(pkg); err == nil {
+		return fmt.Errorf("scaffold: %v already exists", pkg)
+	}
+	items, err := writeMappings(in, filepath.Join(pkg, "mappings"))
+	if err != nil {
+		return err
+	}
+	s.Items = items
+	if err := s.write(pkg); err != nil {
+		return err
+	}
+	fmt.Printf("Created %v. Add packets that changed in the version to its pools and conversions.\n", pkg)
+	return nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/df-mc/worldupgrader/blockupgrader"
+	"github.com/oomph-ac/mv/multiversion/mappings"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+)
+
+// TestWriteMappings tests that JSON dumps are converted to the embedded NBT format.
+func TestWriteMappings(t *testing.T) {
+	dir := t.TempDir()
+	blocks := writeFile(t, dir, "blocks.json", `[
+		{"name": "minecraft:air", "states": {}},
+		{"name": "minecraft:info_update", "states": {}},
+		{"name": "minecraft:stone", "states": {"stone_type": "granite", "lit": true, "age": 3}, "version": 7}
+	]`)
+	items := writeFile(t, dir, "items.json", `{
+		"minecraft:name_tag": {"runtime_id": 1, "component_based": false},
+		"minecraft:stick": {"runtime_id": 2, "component_based": false}
+	}`)
+	if _, err := writeMappings(inputFlags{blocks: blocks}, filepath.Join(dir, "out")); err == nil {
+		t.Errorf("expected an error for block states without a version")
+	}
+
+	out := filepath.Join(dir, "out")
+	if _, err := writeMappings(inputFlags{blocks: blocks, items: items, stateVersion: 5}, out); err != nil {
+		t.Fatalf("write mappings: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(out, mappings.BlockStatesFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec := nbt.NewDecoder(bytes.NewBuffer(data))
+	var states []blockupgrader.BlockState
+	for i := 0; i < 3; i++ {
+		var s blockupgrader.BlockState
+		if err := dec.Decode(&s); err != nil {
+			t.Fatalf("decode block state %v: %v", i, err)
+		}
+		states = append(states, s)
+	}
+	want := blockupgrader.BlockState{
+		Name:       "minecraft:stone",
+		Properties: map[string]any{"stone_type": "granite", "lit": uint8(1), "age": int32(3)},
+		Version:    7,
+	}
+	if !reflect.DeepEqual(states[2], want) || states[0].Version != 5 {
+		t.Errorf("unexpected block states: %+v", states)
+	}
+
+	data, err = os.ReadFile(filepath.Join(out, mappings.ItemRuntimeIDsFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m map[string]int32
+	if err := nbt.Unmarshal(data, &m); err != nil || !reflect.DeepEqual(m, map[string]int32{"minecraft:name_tag": 1, "minecraft:stick": 2}) {
+		t.Errorf("unexpected item table %v (%v)", m, err)
+	}
+}
+
+// TestDecodeJSONItems tests that all supported JSON item table formats are decoded.
+func TestDecodeJSONItems(t *testing.T) {
+	want := map[string]int32{"minecraft:name_tag": 1}
+	for _, data := range []string{
+		`{"minecraft:name_tag": 1}`,
+		`{"minecraft:name_tag": {"runtime_id": 1}}`,
+		`[{"name": "minecraft:name_tag", "id": 1}]`,
+	} {
+		if got, err := decodeJSONItems([]byte(data)); err != nil || !reflect.DeepEqual(got, want) {
+			t.Errorf("%v: expected %v, got %v (%v)", data, want, got, err)
+		}
+	}
+}
+
+// TestScaffold tests that the files of a scaffolded package are created.
+func TestScaffold(t *testing.T) {
+	dir := t.TempDir()
+	if err := (scaffold{Protocol: 999, Version: "1.99.0", Module: "github.com/oomph-ac/mv"}).write(dir); err != nil {
+		t.Fatalf("scaffold: %v", err)
+	}
+	for _, name := range []string{"protocol.go", "protocol_test.go", "mappings.go", "packet/pool.go"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			t.Errorf("expected %v to be created: %v", name, err)
+		}
+	}
+}
+
+// writeFile writes a file with the name and contents passed to a directory and returns its path.
+func writeFile(t *testing.T, dir, name, contents string) string {
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
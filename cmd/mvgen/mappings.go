@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/df-mc/worldupgrader/blockupgrader"
+	"github.com/oomph-ac/mv/multiversion/mappings"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+)
+
+// writeMappings converts the dumps passed to the format embedded in the multi-version packages, validates them and
+// writes them to the directory passed. It returns true if an item table was written.
+func writeMappings(in inputFlags, dir string) (items bool, err error) {
+	var blockStateData, itemRuntimeIDData []byte
+	if in.blocks != "" {
+		if blockStateData, err = readBlockStates(in.blocks, int32(in.stateVersion)); err != nil {
+			return false, err
+		}
+	}
+	if in.items != "" {
+		if itemRuntimeIDData, err = readItems(in.items); err != nil {
+			return false, err
+		}
+	}
+	if err := mappings.Validate(blockStateData, itemRuntimeIDData); err != nil {
+		return false, err
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return false, fmt.Errorf("create %v: %w", dir, err)
+	}
+	for name, data := range map[string][]byte{mappings.BlockStatesFile: blockStateData, mappings.ItemRuntimeIDsFile: itemRuntimeIDData} {
+		if data == nil {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			return false, fmt.Errorf("write %v: %w", name, err)
+		}
+	}
+	return itemRuntimeIDData != nil, nil
+}
+
+// readBlockStates reads a block palette dump and encodes it in the format embedded in the multi-version packages:
+// a sequence of block states in the network little endian NBT encoding. NBT dumps may be encoded in either the
+// network or regular little endian encoding. JSON dumps hold an array of objects with a name, states and version.
+// States without a version in a JSON dump are given the version passed.
+func readBlockStates(path string, version int32) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read block states: %w", err)
+	}
+	var states []blockupgrader.BlockState
+	if strings.HasSuffix(path, ".json") {
+		states, err = decodeJSONBlockStates(data, version)
+	} else if states, err = decodeNBTBlockStates(data, nbt.NetworkLittleEndian); err != nil {
+		states, err = decodeNBTBlockStates(data, nbt.LittleEndian)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode block states %v: %w", path, err)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	enc := nbt.NewEncoderWithEncoding(buf, nbt.NetworkLittleEndian)
+	for _, s := range states {
+		if s.Properties == nil {
+			s.Properties = map[string]any{}
+		}
+		if err := enc.Encode(s); err != nil {
+			return nil, fmt.Errorf("encode block state %v: %w", s.Name, err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeNBTBlockStates decodes a sequence of block states using the NBT encoding passed.
+func decodeNBTBlockStates(data []byte, encoding nbt.Encoding) ([]blockupgrader.BlockState, error) {
+	buf := bytes.NewBuffer(data)
+	dec := nbt.NewDecoderWithEncoding(buf, encoding)
+	var states []blockupgrader.BlockState
+	for buf.Len() > 0 {
+		var s blockupgrader.BlockState
+		if err := dec.Decode(&s); err != nil {
+			return nil, fmt.Errorf("block state %v: %w", len(states), err)
+		}
+		states = append(states, s)
+	}
+	return states, nil
+}
+
+// decodeJSONBlockStates decodes an array of block states in JSON. JSON does not preserve the NBT types of state
+// properties, so booleans are decoded as bytes, numbers as 32-bit integers and strings as strings, which are
+// the only types used by block states.
+func decodeJSONBlockStates(data []byte, version int32) ([]blockupgrader.BlockState, error) {
+	var entries []struct {
+		Name       string         `json:"name"`
+		States     map[string]any `json:"states"`
+		Properties map[string]any `json:"properties"`
+		Version    *int32         `json:"version"`
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	states := make([]blockupgrader.BlockState, 0, len(entries))
+	for i, e := range entries {
+		if e.Version == nil {
+			if version == 0 {
+				return nil, fmt.Errorf("block state %v (%v) has no version: pass -state-version", i, e.Name)
+			}
+			e.Version = &version
+		}
+		if e.States == nil {
+			e.States = e.Properties
+		}
+		properties := make(map[string]any, len(e.States))
+		for k, v := range e.States {
+			switch v := v.(type) {
+			case bool:
+				properties[k] = boolByte(v)
+			case float64:
+				if v != math.Trunc(v) || v < math.MinInt32 || v > math.MaxInt32 {
+					return nil, fmt.Errorf("block state %v (%v): property %v is not a 32-bit integer", i, e.Name, k)
+				}
+				properties[k] = int32(v)
+			case string:
+				properties[k] = v
+			default:
+				return nil, fmt.Errorf("block state %v (%v): property %v has unsupported type %T", i, e.Name, k, v)
+			}
+		}
+		states = append(states, blockupgrader.BlockState{Name: e.Name, Properties: properties, Version: *e.Version})
+	}
+	return states, nil
+}
+
+// readItems reads an item table dump and encodes it in the format embedded in the multi-version packages: a
+// compound of item names and runtime IDs in the network little endian NBT encoding. NBT dumps hold such a
+// compound in either the network or regular little endian encoding. JSON dumps may hold an object of names and
+// runtime IDs, an object of names and objects with a runtime_id, as exported by a dedicated server, or an array of
+// objects with a name and id.
+func readItems(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read items: %w", err)
+	}
+	var items map[string]int32
+	if strings.HasSuffix(path, ".json") {
+		items, err = decodeJSONItems(data)
+	} else if err = nbt.Unmarshal(data, &items); err != nil {
+		err = nbt.UnmarshalEncoding(data, &items, nbt.LittleEndian)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode items %v: %w", path, err)
+	}
+	return nbt.Marshal(items)
+}
+
+// decodeJSONItems decodes an item table in any of the JSON formats supported by readItems.
+func decodeJSONItems(data []byte) (map[string]int32, error) {
+	items := map[string]int32{}
+	var list []struct {
+		Name string `json:"name"`
+		ID   int32  `json:"id"`
+	}
+	if err := json.Unmarshal(data, &list); err == nil {
+		for _, e := range list {
+			items[e.Name] = e.ID
+		}
+		return items, nil
+	}
+	var entries map[string]json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	for name, raw := range entries {
+		var id int32
+		if err := json.Unmarshal(raw, &id); err == nil {
+			items[name] = id
+			continue
+		}
+		var entry struct {
+			RuntimeID *int32 `json:"runtime_id"`
+		}
+		if err := json.Unmarshal(raw, &entry); err != nil || entry.RuntimeID == nil {
+			return nil, fmt.Errorf("item %v has no runtime ID", name)
+		}
+		items[name] = *entry.RuntimeID
+	}
+	return items, nil
+}
+
+// boolByte returns 1 if b is true, or 0 if it is false.
+func boolByte(b bool) uint8 {
+	if b {
+		return 1
+	}
+	return 0
+}
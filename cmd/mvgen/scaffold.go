@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// scaffold holds the data used to create the package of a new version.
+type scaffold struct {
+	// Protocol is the protocol ID of the version, such as 671.
+	Protocol int
+	// Version is the version, such as "1.20.80".
+	Version string
+	// Module is the path of the module the package is created in.
+	Module string
+	// Items specifies if the package has an item table of its own. If false, the item table of the latest version
+	// is used.
+	Items bool
+}
+
+// write writes the files of the package to the directory passed.
+func (s scaffold) write(dir string) error {
+	for name, tmpl := range map[string]*template.Template{
+		"protocol.go":      protocolTemplate,
+		"protocol_test.go": protocolTestTemplate,
+		"mappings.go":      mappingsTemplate,
+		"packet/pool.go":   poolTemplate,
+	} {
+		buf := bytes.NewBuffer(nil)
+		if err := tmpl.Execute(buf, s); err != nil {
+			return fmt.Errorf("scaffold %v: %w", name, err)
+		}
+		src, err := format.Source(buf.Bytes())
+		if err != nil {
+			return fmt.Errorf("scaffold %v: %w", name, err)
+		}
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return fmt.Errorf("scaffold %v: %w", name, err)
+		}
+		if err := os.WriteFile(path, src, 0644); err != nil {
+			return fmt.Errorf("scaffold %v: %w", name, err)
+		}
+	}
+	return nil
+}
+
+var protocolTemplate = template.Must(template.New("protocol.go").Parse(`package mv{{.Protocol}}
+
+import (
+	"sync"
+
+	"{{.Module}}/multiversion/capability"
+	"{{.Module}}/multiversion/mappings"
+	"{{.Module}}/multiversion/mv{{.Protocol}}/packet"
+	"{{.Module}}/multiversion/util"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	gtpacket "github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+var capabilities = sync.OnceValue(func() capability.Set {
+	return capability.Derive(packet.NewServerPool(), packet.NewClientPool(), Mapping())
+})
+
+type Protocol struct{}
+
+func (Protocol) ID() int32 {
+	return {{.Protocol}}
+}
+
+func (Protocol) Ver() string {
+	return "{{.Version}}"
+}
+
+func (Protocol) NewReader(r minecraft.ByteReader, shieldID int32, enableLimits bool) protocol.IO {
+	return protocol.NewReader(r, shieldID, enableLimits)
+}
+
+func (Protocol) NewWriter(r minecraft.ByteWriter, shieldID int32) protocol.IO {
+	return protocol.NewWriter(r, shieldID)
+}
+
+func (Protocol) Packets(listener bool) gtpacket.Pool {
+	if listener {
+		return packet.NewClientPool()
+	}
+	return packet.NewServerPool()
+}
+
+func (Protocol) Encryption(key [32]byte) gtpacket.Encryption {
+	return gtpacket.NewCTREncryption(key[:])
+}
+
+func (Protocol) Mapping() mappings.MVMapping {
+	return Mapping()
+}
+
+func (Protocol) Capabilities() capability.Set {
+	return capabilities()
+}
+
+func (Protocol) ConvertToLatest(pk gtpacket.Packet, conn *minecraft.Conn) (packets []gtpacket.Packet) {
+	defer util.RecoverMalformed(conn, pk, &packets)
+	return util.UpgradePacket(conn, pk, Mapping(), Upgrade)
+}
+
+func (Protocol) ConvertFromLatest(pk gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
+	if downgraded, ok := util.DefaultDowngrade(conn, pk, Mapping()); ok {
+		return Downgrade([]gtpacket.Packet{downgraded}, conn)
+	}
+
+	return Downgrade([]gtpacket.Packet{pk}, conn)
+}
+
+func Upgrade(pks []gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
+	packets := make([]gtpacket.Packet, 0, len(pks))
+	for _, pk := range pks {
+		// TODO: Upgrade the packets that changed in this version.
+		switch pk := pk.(type) {
+		default:
+			packets = append(packets, pk)
+		}
+	}
+
+	return packets
+}
+
+func Downgrade(pks []gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
+	packets := make([]gtpacket.Packet, 0, len(pks))
+	for _, pk := range pks {
+		// TODO: Downgrade the packets that changed in this version.
+		switch pk := pk.(type) {
+		default:
+			packets = append(packets, pk)
+		}
+	}
+
+	return packets
+}
+`))
+
+var protocolTestTemplate = template.Must(template.New("protocol_test.go").Parse(`package mv{{.Protocol}}
+
+import (
+	"testing"
+
+	"{{.Module}}/multiversion/internal/conformance"
+	"{{.Module}}/multiversion/util"
+	"github.com/sandertv/gophertunnel/minecraft"
+	gtpacket "github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// TestConformance round-trips every packet overridden by the protocol through a downgrade and upgrade.
+func TestConformance(t *testing.T) {
+	conformance.Test(t, Protocol{})
+}
+
+// FuzzConvertToLatest fuzzes the conversion of packets read from a connection to the latest version.
+func FuzzConvertToLatest(f *testing.F) {
+	conformance.Fuzz(f, Protocol{}, func(pk gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
+		return util.UpgradePacket(conn, pk, Mapping(), Upgrade)
+	})
+}
+`))
+
+var mappingsTemplate = template.Must(template.New("mappings.go").Parse(`package mv{{.Protocol}}
+
+import (
+	_ "embed"
+	"sync"
+{{if not .Items}}
+	"{{.Module}}/multiversion/latest"{{end}}
+	"{{.Module}}/multiversion/mappings"
+)
+
+var (
+	//go:embed mappings/block_states.nbt
+	blockStates []byte{{if .Items}}
+	//go:embed mappings/item_runtime_ids.nbt
+	itemRuntimeIDs []byte{{end}}
+)
+
+func init() {
+	mappings.Register(Protocol{}.ID(), blockStates)
+}
+
+// Mapping returns the block and item mappings of the protocol. They are loaded on first use and shared with
+// other protocols using the same block palette or item table. A block palette set using mappings.SetOverrides
+// replaces the embedded palette, while an item table set using it is layered on top of the embedded table.
+var Mapping = sync.OnceValue(func() mappings.MVMapping {
+	return mappings.Load(Protocol{}.ID(), blockStates, {{if .Items}}itemRuntimeIDs{{else}}latest.ItemRuntimeIDData{{end}}, false)
+})
+`))
+
+var poolTemplate = template.Must(template.New("pool.go").Parse(`package packet
+
+import (
+	gtpacket "github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// TODO: Add the IDs and pool entries of packets that changed in this version.
+
+func NewClientPool() gtpacket.Pool {
+	pool := gtpacket.NewClientPool()
+
+	return pool
+}
+
+func NewServerPool() gtpacket.Pool {
+	pool := gtpacket.NewServerPool()
+
+	return pool
+}
+`))
@@ -0,0 +1,325 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	vers "github.com/oomph-ac/mv"
+	"github.com/oomph-ac/mv/multiversion/mappings"
+	"github.com/oomph-ac/mv/multiversion/mv567"
+	"github.com/oomph-ac/mv/multiversion/mv568"
+	"github.com/oomph-ac/mv/multiversion/mv575"
+	"github.com/oomph-ac/mv/multiversion/mv582"
+	"github.com/oomph-ac/mv/multiversion/mv589"
+	"github.com/oomph-ac/mv/multiversion/mv594"
+	"github.com/oomph-ac/mv/multiversion/mv618"
+	"github.com/oomph-ac/mv/multiversion/mv622"
+	"github.com/oomph-ac/mv/multiversion/mv630"
+	"github.com/oomph-ac/mv/multiversion/mv649"
+	"github.com/oomph-ac/mv/multiversion/mv662"
+	"github.com/pelletier/go-toml"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/auth"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/oauth2"
+)
+
+// protocols holds all protocols the proxy is able to accept, other than the latest one.
+var protocols = []minecraft.Protocol{
+	mv567.Protocol{},
+	mv568.Protocol{},
+	mv575.Protocol{},
+	mv582.Protocol{},
+	mv589.Protocol{},
+	mv594.Protocol{},
+	mv618.Protocol{},
+	mv622.Protocol{},
+	mv630.Protocol{},
+	mv649.Protocol{},
+	mv662.Protocol{},
+}
+
+// The following program implements a proxy that accepts clients of any supported version and relays them to a
+// backend server that only speaks the latest protocol. Packets are translated in both directions by the
+// protocols the clients joined with.
+func main() {
+	log := logrus.New()
+	log.Formatter = &logrus.TextFormatter{ForceColors: true}
+	log.Level = logrus.InfoLevel
+
+	c, err := readConfig()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	accepted, err := c.protocols()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if c.Server.Mappings != "" {
+		if err := mappings.SetOverrides(os.DirFS(c.Server.Mappings)); err != nil {
+			log.Fatalln(err)
+		}
+	}
+	var acc *account
+	if c.Network.BackendAuth {
+		src, err := tokenSource()
+		if err != nil {
+			log.Fatalln(err)
+		}
+		acc = &account{src: src}
+		log.Info("Relaying with an XBOX Live account: only one player can join at a time.")
+	} else {
+		log.Infof("Relaying without XBOX Live authentication: %v must have authentication disabled.", c.Network.Backend)
+	}
+	vers.Preload(accepted...)
+
+	listener, err := minecraft.ListenConfig{
+		StatusProvider:         minecraft.NewStatusProvider(c.Server.Name),
+		AuthenticationDisabled: c.Server.AuthDisabled,
+		MaximumPlayers:         c.Server.MaxPlayers,
+		AcceptedProtocols:      accepted,
+	}.Listen("raknet", c.Network.Address)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	defer listener.Close()
+	log.Infof("Proxy running on %v, relaying to %v.", listener.Addr(), c.Network.Backend)
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go handleConn(conn.(*minecraft.Conn), listener, c, acc, log)
+	}
+}
+
+// handleConn relays the minecraft.Conn passed to the backend server. Packets read from the client are converted
+// to the latest protocol by the minecraft.Conn, while packets written to it are converted to the protocol of the
+// client. If acc is not nil, the proxy logs in to the backend server using the XBOX Live account of acc, and
+// clients joining while another client uses the account are disconnected.
+func handleConn(conn *minecraft.Conn, listener *minecraft.Listener, c config, acc *account, log *logrus.Logger) {
+	var src oauth2.TokenSource
+	release := func() {}
+	if acc != nil {
+		if !acc.inUse.CompareAndSwap(false, true) {
+			log.Warnf("%v: rejected: the XBOX Live account is in use by another player", conn.IdentityData().DisplayName)
+			_ = listener.Disconnect(conn, "Another player is already connected through this proxy.")
+			return
+		}
+		src, release = acc.src, func() { acc.inUse.Store(false) }
+	}
+
+	serverConn, err := minecraft.Dialer{
+		IdentityData:        conn.IdentityData(),
+		ClientData:          conn.ClientData(),
+		KeepXBLIdentityData: true,
+		TokenSource:         src,
+	}.Dial("raknet", c.Network.Backend)
+	if err != nil {
+		release()
+		log.Errorf("%v: dial backend: %v", conn.IdentityData().DisplayName, err)
+		_ = listener.Disconnect(conn, "Could not connect to the server.")
+		return
+	}
+	log.Infof("%v joined with version %v (%v).", conn.IdentityData().DisplayName, conn.Protocol().Ver(), conn.Protocol().ID())
+
+	var g sync.WaitGroup
+	g.Add(2)
+	errs := make(chan error, 2)
+	go func() {
+		defer g.Done()
+		errs <- conn.StartGame(serverConn.GameData())
+	}()
+	go func() {
+		defer g.Done()
+		errs <- serverConn.DoSpawn()
+	}()
+	g.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			log.Errorf("%v: spawn: %v", conn.IdentityData().DisplayName, err)
+			_ = serverConn.Close()
+			_ = listener.Disconnect(conn, "Could not spawn on the server.")
+			release()
+			return
+		}
+	}
+
+	var relay sync.WaitGroup
+	relay.Add(2)
+	go func() {
+		// The account is only released once both connections are closed, so that the backend server does not
+		// see two sessions of it at once.
+		relay.Wait()
+		release()
+	}()
+	go func() {
+		defer relay.Done()
+		defer listener.Disconnect(conn, "Connection lost.")
+		defer serverConn.Close()
+		for {
+			pk, err := conn.ReadPacket()
+			if err != nil {
+				return
+			}
+			if err := serverConn.WritePacket(pk); err != nil {
+				var disconnect minecraft.DisconnectError
+				if errors.As(err, &disconnect) {
+					_ = listener.Disconnect(conn, disconnect.Error())
+				}
+				return
+			}
+		}
+	}()
+	go func() {
+		defer relay.Done()
+		defer serverConn.Close()
+		defer listener.Disconnect(conn, "Connection lost.")
+		for {
+			pk, err := serverConn.ReadPacket()
+			if err != nil {
+				var disconnect minecraft.DisconnectError
+				if errors.As(err, &disconnect) {
+					_ = listener.Disconnect(conn, disconnect.Error())
+				}
+				return
+			}
+			if err := conn.WritePacket(pk); err != nil {
+				return
+			}
+		}
+	}()
+}
+
+// account is the XBOX Live account that the proxy logs in to the backend server with. The backend server only
+// allows one session per account, so it is used by a single client at a time.
+type account struct {
+	src   oauth2.TokenSource
+	inUse atomic.Bool
+}
+
+// tokenSource returns an oauth2.TokenSource of the XBOX Live account that the proxy logs in to the backend
+// server with. The token is read from the token.tok file if present, or requested through device
+// authentication otherwise, and written back to the file after refreshing it.
+func tokenSource() (oauth2.TokenSource, error) {
+	token := new(oauth2.Token)
+	if data, err := os.ReadFile("token.tok"); err == nil {
+		if err := json.Unmarshal(data, token); err != nil {
+			return nil, fmt.Errorf("decode token: %v", err)
+		}
+	} else if token, err = auth.RequestLiveToken(); err != nil {
+		return nil, fmt.Errorf("request token: %v", err)
+	}
+	src := auth.RefreshTokenSource(token)
+	refreshed, err := src.Token()
+	if err != nil {
+		// The cached token could not be refreshed, so a new one is requested.
+		if token, err = auth.RequestLiveToken(); err != nil {
+			return nil, fmt.Errorf("request token: %v", err)
+		}
+		src = auth.RefreshTokenSource(token)
+		if refreshed, err = src.Token(); err != nil {
+			return nil, fmt.Errorf("refresh token: %v", err)
+		}
+	}
+	data, err := json.Marshal(refreshed)
+	if err != nil {
+		return nil, fmt.Errorf("encode token: %v", err)
+	}
+	if err := os.WriteFile("token.tok", data, 0600); err != nil {
+		return nil, fmt.Errorf("write token: %v", err)
+	}
+	return src, nil
+}
+
+// config is the configuration of the proxy, read from the config.toml file.
+type config struct {
+	Network struct {
+		// Address is the address the proxy listens on.
+		Address string
+		// Backend is the address of the server that clients are relayed to. The server must use the latest
+		// protocol.
+		Backend string
+		// BackendAuth specifies if the proxy logs in to the backend server with an XBOX Live account. The
+		// account is requested through device authentication when the proxy starts and its token is cached in
+		// the token.tok file. Clients are then relayed using the identity of that account. As the backend server
+		// allows only one session per account, only a single player is supported: Players joining while another
+		// player is connected are disconnected with a message. If false, the backend server must have XBOX Live
+		// authentication disabled.
+		BackendAuth bool
+	}
+	Server struct {
+		// Name is the name of the proxy shown in the server list.
+		Name string
+		// AuthDisabled specifies if XBOX Live authentication of clients should be disabled.
+		AuthDisabled bool
+		// MaxPlayers is the maximum amount of players accepted. If zero, any amount of players is accepted.
+		MaxPlayers int
+		// Versions holds the versions accepted by the proxy, such as "1.20.0". If empty, all versions are
+		// accepted. The latest version is always accepted.
+		Versions []string
+		// Mappings is the path of a directory holding mappings that override the embedded mappings of the
+		// accepted versions. It holds a directory for every protocol overridden, named after its protocol ID. If
+		// empty, the embedded mappings are used as is.
+		Mappings string
+	}
+}
+
+// protocols returns the protocols of the versions in the config.
+func (c config) protocols() ([]minecraft.Protocol, error) {
+	if len(c.Server.Versions) == 0 {
+		return protocols, nil
+	}
+	accepted := make([]minecraft.Protocol, 0, len(c.Server.Versions))
+	for _, ver := range c.Server.Versions {
+		found := false
+		for _, p := range protocols {
+			if p.Ver() == ver {
+				accepted, found = append(accepted, p), true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("config: unsupported version %v", ver)
+		}
+	}
+	return accepted, nil
+}
+
+// defaultConfig returns the config written to the config.toml file if it does not yet exist.
+func defaultConfig() config {
+	var c config
+	c.Network.Address = "0.0.0.0:19132"
+	c.Network.Backend = "127.0.0.1:19133"
+	c.Server.Name = "Multi-version Proxy"
+	return c
+}
+
+// readConfig reads the configuration from the config.toml file, or creates the file if it does not yet exist.
+func readConfig() (config, error) {
+	c := defaultConfig()
+	if _, err := os.Stat("config.toml"); os.IsNotExist(err) {
+		data, err := toml.Marshal(c)
+		if err != nil {
+			return c, fmt.Errorf("encode default config: %v", err)
+		}
+		if err := os.WriteFile("config.toml", data, 0644); err != nil {
+			return c, fmt.Errorf("create default config: %v", err)
+		}
+		return c, nil
+	}
+	data, err := os.ReadFile("config.toml")
+	if err != nil {
+		return c, fmt.Errorf("read config: %v", err)
+	}
+	if err := toml.Unmarshal(data, &c); err != nil {
+		return c, fmt.Errorf("decode config: %v", err)
+	}
+	return c, nil
+}
@@ -0,0 +1,312 @@
+package vers
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/google/uuid"
+	"github.com/oomph-ac/mv/multiversion/latest"
+	"github.com/oomph-ac/mv/multiversion/mv567"
+	"github.com/oomph-ac/mv/multiversion/mv568"
+	"github.com/oomph-ac/mv/multiversion/mv575"
+	"github.com/oomph-ac/mv/multiversion/mv582"
+	"github.com/oomph-ac/mv/multiversion/mv589"
+	"github.com/oomph-ac/mv/multiversion/mv594"
+	"github.com/oomph-ac/mv/multiversion/mv618"
+	"github.com/oomph-ac/mv/multiversion/mv622"
+	"github.com/oomph-ac/mv/multiversion/mv630"
+	"github.com/oomph-ac/mv/multiversion/mv649"
+	"github.com/oomph-ac/mv/multiversion/mv662"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// TestProtocolDirections tests that packets sent by either side of a connection survive a conversion to a legacy
+// protocol and back, so that the protocols may be used by a minecraft.Dialer as well as a minecraft.Listener.
+func TestProtocolDirections(t *testing.T) {
+	protocols := []minecraft.Protocol{
+		mv567.Protocol{},
+		mv568.Protocol{},
+		mv575.Protocol{},
+		mv582.Protocol{},
+		mv589.Protocol{},
+		mv594.Protocol{},
+		mv618.Protocol{},
+		mv622.Protocol{},
+		mv630.Protocol{},
+		mv649.Protocol{},
+		mv662.Protocol{},
+	}
+	tests := []struct {
+		// fromClient specifies if the packet is sent by the client.
+		fromClient bool
+		pk         func() packet.Packet
+	}{
+		{fromClient: true, pk: func() packet.Packet {
+			return &packet.PlayerAuthInput{Position: mgl32.Vec3{1, 2, 3}, Tick: 20, InputMode: packet.InputModeMouse}
+		}},
+		{fromClient: true, pk: func() packet.Packet {
+			return &packet.LecternUpdate{Page: 2, PageCount: 4, Position: protocol.BlockPos{1, 2, 3}}
+		}},
+		{pk: func() packet.Packet {
+			return &packet.StartGame{WorldName: "world", BaseGameVersion: "1.20.0", EntityUniqueID: 1, EntityRuntimeID: 1, Items: latest.ItemEntries()}
+		}},
+		{pk: func() packet.Packet {
+			return &packet.ItemComponent{Items: []protocol.ItemComponentEntry{{Name: "test:item", Data: map[string]any{
+				"components": map[string]any{"item_properties": map[string]any{
+					"minecraft:icon": map[string]any{"textures": map[string]any{"default": "item"}},
+				}},
+			}}}}
+		}},
+		{pk: func() packet.Packet { return &packet.Disconnect{Message: "bye"} }},
+		{pk: func() packet.Packet { return &packet.ResourcePacksInfo{TexturePackRequired: true} }},
+		{pk: func() packet.Packet { return &packet.ResourcePackStack{BaseGameVersion: "*"} }},
+		{pk: func() packet.Packet { return &packet.MobEffect{EntityRuntimeID: 1, EffectType: 3, Duration: 20} }},
+		{pk: func() packet.Packet { return &packet.SetActorMotion{EntityRuntimeID: 1, Velocity: mgl32.Vec3{0, 1, 0}} }},
+		{pk: func() packet.Packet { return &packet.UpdatePlayerGameType{GameType: 1, PlayerUniqueID: 1} }},
+		{pk: func() packet.Packet { return &packet.ShowStoreOffer{OfferID: "offer"} }},
+		{pk: func() packet.Packet {
+			return &packet.LevelChunk{Position: protocol.ChunkPos{1, 2}, SubChunkCount: protocol.SubChunkRequestModeLimitless}
+		}},
+		{pk: func() packet.Packet {
+			return &packet.AvailableCommands{
+				EnumValues:   []string{"a", "b"},
+				Suffixes:     []string{"L"},
+				Enums:        []protocol.CommandEnum{{Type: "enum", ValueIndices: []uint{0, 1}}},
+				DynamicEnums: []protocol.DynamicEnum{{Type: "soft", Values: []string{"c"}}},
+				Commands: []protocol.Command{{
+					Name: "say",
+					Overloads: []protocol.CommandOverload{{Parameters: []protocol.CommandParameter{
+						{Name: "message", Type: protocol.CommandArgValid | protocol.CommandArgTypeString},
+						{Name: "count", Type: protocol.CommandArgValid | protocol.CommandArgTypeInt},
+						{Name: "enum", Type: protocol.CommandArgValid | protocol.CommandArgEnum},
+						{Name: "soft", Type: protocol.CommandArgValid | protocol.CommandArgSoftEnum},
+						{Name: "levels", Type: protocol.CommandArgSuffixed},
+					}}},
+				}},
+			}
+		}},
+	}
+
+	conn := &minecraft.Conn{}
+	for _, proto := range protocols {
+		for _, test := range tests {
+			want := test.pk()
+
+			var converted []packet.Packet
+			for _, pk := range proto.ConvertFromLatest(test.pk(), conn) {
+				// The listener reads packets from the client with Packets(true), the dialer reads packets from
+				// the server with Packets(false).
+				pk = encodeDecode(t, proto, pk, proto.Packets(test.fromClient))
+				converted = append(converted, proto.ConvertToLatest(pk, conn)...)
+			}
+			if len(converted) != 1 {
+				t.Errorf("%v: %T: expected 1 packet, got %v", proto.Ver(), want, len(converted))
+				continue
+			}
+			if reflect.TypeOf(converted[0]) != reflect.TypeOf(want) || !bytes.Equal(encode(converted[0]), encode(want)) {
+				t.Errorf("%v: %T: round trip mismatch:\nwant %+v\ngot  %+v", proto.Ver(), want, want, converted[0])
+			}
+		}
+	}
+}
+
+// TestCommandPackets tests that the CommandRequest, SettingsCommand and CommandOutput packets are sent in the
+// format of every protocol. The format of these packets did not change between 1.19.50 and the latest version,
+// so they are not converted: The packets are written by the legacy encoders below, transcribed from those of
+// gophertunnel for 1.19.70, and must decode to the same packet for every protocol, while the latest packets
+// must encode to the same bytes.
+func TestCommandPackets(t *testing.T) {
+	protocols := []minecraft.Protocol{
+		mv567.Protocol{},
+		mv568.Protocol{},
+		mv575.Protocol{},
+		mv582.Protocol{},
+		mv589.Protocol{},
+		mv594.Protocol{},
+		mv618.Protocol{},
+		mv622.Protocol{},
+		mv630.Protocol{},
+		mv649.Protocol{},
+		mv662.Protocol{},
+	}
+	tests := []struct {
+		fromClient bool
+		legacy     interface{ Marshal(w *protocol.Writer) }
+		want       packet.Packet
+	}{
+		{
+			fromClient: true,
+			legacy:     &legacyCommandRequest{CommandLine: "/say hi", Origin: protocol.CommandOriginPlayer, UUID: commandUUID, Version: 36},
+			want:       &packet.CommandRequest{CommandLine: "/say hi", CommandOrigin: protocol.CommandOrigin{Origin: protocol.CommandOriginPlayer, UUID: commandUUID}, Version: 36},
+		},
+		{
+			fromClient: true,
+			legacy:     &legacyCommandRequest{CommandLine: "/list", Origin: protocol.CommandOriginDevConsole, UUID: commandUUID, RequestID: "request", PlayerUniqueID: 1, Internal: true},
+			want:       &packet.CommandRequest{CommandLine: "/list", CommandOrigin: protocol.CommandOrigin{Origin: protocol.CommandOriginDevConsole, UUID: commandUUID, RequestID: "request", PlayerUniqueID: 1}, Internal: true},
+		},
+		{
+			fromClient: true,
+			legacy:     &legacySettingsCommand{CommandLine: "/gamerule dodaylightcycle false", SuppressOutput: true},
+			want:       &packet.SettingsCommand{CommandLine: "/gamerule dodaylightcycle false", SuppressOutput: true},
+		},
+		{
+			legacy: &legacyCommandOutput{Origin: protocol.CommandOriginPlayer, UUID: commandUUID, OutputType: packet.CommandOutputTypeAllOutput, SuccessCount: 1, Messages: []legacyCommandOutputMessage{
+				{Success: true, Message: "commands.say.message", Parameters: []string{"hi"}},
+			}},
+			want: &packet.CommandOutput{
+				CommandOrigin:  protocol.CommandOrigin{Origin: protocol.CommandOriginPlayer, UUID: commandUUID},
+				OutputType:     packet.CommandOutputTypeAllOutput,
+				SuccessCount:   1,
+				OutputMessages: []protocol.CommandOutputMessage{{Success: true, Message: "commands.say.message", Parameters: []string{"hi"}}},
+			},
+		},
+		{
+			legacy: &legacyCommandOutput{Origin: protocol.CommandOriginDevConsole, UUID: commandUUID, PlayerUniqueID: 1, OutputType: packet.CommandOutputTypeDataSet, DataSet: "{}"},
+			want: &packet.CommandOutput{
+				CommandOrigin:  protocol.CommandOrigin{Origin: protocol.CommandOriginDevConsole, UUID: commandUUID, PlayerUniqueID: 1},
+				OutputType:     packet.CommandOutputTypeDataSet,
+				OutputMessages: []protocol.CommandOutputMessage{},
+				DataSet:        "{}",
+			},
+		},
+	}
+
+	conn := &minecraft.Conn{}
+	for _, proto := range protocols {
+		for _, test := range tests {
+			buf := bytes.NewBuffer(nil)
+			test.legacy.Marshal(protocol.NewWriter(buf, 0))
+			legacy := buf.Bytes()
+
+			f, ok := proto.Packets(test.fromClient)[test.want.ID()]
+			if !ok {
+				t.Fatalf("%v: %T: packet not in pool", proto.Ver(), test.want)
+			}
+			decoded := f()
+			decoded.Marshal(proto.NewReader(bytes.NewBuffer(legacy), 0, false))
+			if converted := proto.ConvertToLatest(decoded, conn); len(converted) != 1 || !reflect.DeepEqual(converted[0], test.want) {
+				t.Errorf("%v: %T: decoding legacy bytes:\nwant %+v\ngot  %+v", proto.Ver(), test.want, test.want, converted)
+			}
+
+			converted := proto.ConvertFromLatest(test.want, conn)
+			if len(converted) != 1 {
+				t.Errorf("%v: %T: expected 1 packet, got %v", proto.Ver(), test.want, len(converted))
+				continue
+			}
+			buf.Reset()
+			converted[0].Marshal(proto.NewWriter(buf, 0))
+			if !bytes.Equal(buf.Bytes(), legacy) {
+				t.Errorf("%v: %T: expected legacy bytes %x, got %x", proto.Ver(), test.want, legacy, buf.Bytes())
+			}
+		}
+	}
+}
+
+// commandUUID is the UUID of the command origins of the command packets tested.
+var commandUUID = uuid.MustParse("7b1a0c6e-4f8a-4d7e-9c1b-2a6f5e3d8c90")
+
+// legacyCommandRequest is a CommandRequest packet as encoded by gophertunnel for 1.19.70.
+type legacyCommandRequest struct {
+	CommandLine    string
+	Origin         uint32
+	UUID           uuid.UUID
+	RequestID      string
+	PlayerUniqueID int64
+	Internal       bool
+	Version        int32
+}
+
+func (pk *legacyCommandRequest) Marshal(w *protocol.Writer) {
+	w.String(&pk.CommandLine)
+	legacyCommandOrigin(w, &pk.Origin, &pk.UUID, &pk.RequestID, &pk.PlayerUniqueID)
+	w.Bool(&pk.Internal)
+	w.Varint32(&pk.Version)
+}
+
+// legacySettingsCommand is a SettingsCommand packet as encoded by gophertunnel for 1.19.70.
+type legacySettingsCommand struct {
+	CommandLine    string
+	SuppressOutput bool
+}
+
+func (pk *legacySettingsCommand) Marshal(w *protocol.Writer) {
+	w.String(&pk.CommandLine)
+	w.Bool(&pk.SuppressOutput)
+}
+
+// legacyCommandOutput is a CommandOutput packet as encoded by gophertunnel for 1.19.70.
+type legacyCommandOutput struct {
+	Origin         uint32
+	UUID           uuid.UUID
+	RequestID      string
+	PlayerUniqueID int64
+	OutputType     byte
+	SuccessCount   uint32
+	Messages       []legacyCommandOutputMessage
+	DataSet        string
+}
+
+// legacyCommandOutputMessage is an output message of a legacyCommandOutput.
+type legacyCommandOutputMessage struct {
+	Success    bool
+	Message    string
+	Parameters []string
+}
+
+func (pk *legacyCommandOutput) Marshal(w *protocol.Writer) {
+	legacyCommandOrigin(w, &pk.Origin, &pk.UUID, &pk.RequestID, &pk.PlayerUniqueID)
+	w.Uint8(&pk.OutputType)
+	w.Varuint32(&pk.SuccessCount)
+	count := uint32(len(pk.Messages))
+	w.Varuint32(&count)
+	for _, m := range pk.Messages {
+		w.Bool(&m.Success)
+		w.String(&m.Message)
+		count := uint32(len(m.Parameters))
+		w.Varuint32(&count)
+		for _, p := range m.Parameters {
+			w.String(&p)
+		}
+	}
+	if pk.OutputType == packet.CommandOutputTypeDataSet {
+		w.String(&pk.DataSet)
+	}
+}
+
+// legacyCommandOrigin writes a command origin as encoded by gophertunnel for 1.19.70.
+func legacyCommandOrigin(w *protocol.Writer, origin *uint32, id *uuid.UUID, requestID *string, playerUniqueID *int64) {
+	w.Varuint32(origin)
+	w.UUID(id)
+	w.String(requestID)
+	if *origin == protocol.CommandOriginDevConsole || *origin == protocol.CommandOriginTest {
+		w.Varint64(playerUniqueID)
+	}
+}
+
+// encodeDecode encodes the packet passed using the protocol and decodes it again using the pool passed.
+func encodeDecode(t *testing.T, proto minecraft.Protocol, pk packet.Packet, pool packet.Pool) packet.Packet {
+	buf := bytes.NewBuffer(nil)
+	pk.Marshal(proto.NewWriter(buf, 0))
+
+	f, ok := pool[pk.ID()]
+	if !ok {
+		t.Fatalf("%v: %T: packet %v not in pool", proto.Ver(), pk, pk.ID())
+	}
+	decoded := f()
+	decoded.Marshal(proto.NewReader(buf, 0, false))
+	if buf.Len() != 0 {
+		t.Errorf("%v: %T: %v bytes left after decoding", proto.Ver(), pk, buf.Len())
+	}
+	return decoded
+}
+
+// encode encodes the packet passed using the latest protocol.
+func encode(pk packet.Packet) []byte {
+	buf := bytes.NewBuffer(nil)
+	pk.Marshal(protocol.NewWriter(buf, 0))
+	return buf.Bytes()
+}
@@ -0,0 +1,41 @@
+package vers
+
+import (
+	"net"
+
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/df-mc/dragonfly/server/session"
+	"github.com/sandertv/gophertunnel/minecraft"
+)
+
+// Handler handles events that are called by a Vers listener. Implementations of Handler may be used to act on
+// the protocol version of connections before they are handed to Dragonfly.
+type Handler interface {
+	// HandleJoin handles a connection that finished logging in with one of the accepted protocols. The
+	// connection has not yet been spawned in a world. ctx.Cancel() may be called to disconnect the connection
+	// with the message held by *message. HandleJoin is called in a separate goroutine for every connection, so
+	// it may block without holding up other connections that are joining.
+	HandleJoin(ctx *event.Context, conn session.Conn, proto minecraft.Protocol, message *string)
+}
+
+// UnsupportedProtocolHandler may be implemented by a Handler to be notified of clients attempting to join with
+// a protocol that is not accepted.
+type UnsupportedProtocolHandler interface {
+	// HandleUnsupportedProtocol handles a client attempting to join with a protocol that is not accepted. The
+	// client is disconnected with a message listing the supported versions.
+	HandleUnsupportedProtocol(addr net.Addr, protocol int32)
+}
+
+// NopHandler implements the Handler interface but does not execute any code when an event is called. The
+// default Handler of a Vers instance is set to NopHandler.
+// Users may embed NopHandler to avoid having to implement each method.
+type NopHandler struct{}
+
+// Compile time check to make sure NopHandler implements Handler and UnsupportedProtocolHandler.
+var (
+	_ Handler                    = NopHandler{}
+	_ UnsupportedProtocolHandler = NopHandler{}
+)
+
+func (NopHandler) HandleJoin(*event.Context, session.Conn, minecraft.Protocol, *string) {}
+func (NopHandler) HandleUnsupportedProtocol(net.Addr, int32)                            {}
@@ -1,25 +1,105 @@
 package vers
 
 import (
+	"github.com/df-mc/dragonfly/server/event"
 	"github.com/df-mc/dragonfly/server/session"
+	"github.com/google/uuid"
 	"github.com/sandertv/gophertunnel/minecraft"
 )
 
-// listener is a custom minecraft.Listener for multi-version support.
+// listener is a custom minecraft.Listener for multi-version support. Connections are accepted in the
+// background and the Handler of the Vers instance is called for each of them in its own goroutine, so that a
+// slow HandleJoin does not hold up other connections that are joining.
 type listener struct {
 	*minecraft.Listener
+	v *Vers
+
+	// incoming holds connections that have joined and may be returned by Accept.
+	incoming chan *conn
+	// closed is closed once the minecraft.Listener no longer accepts connections. err is the error it
+	// returned.
+	closed chan struct{}
+	err    error
 }
 
-// Accept accepts an incoming connection.
-func (l listener) Accept() (session.Conn, error) {
-	conn, err := l.Listener.Accept()
+// newListener creates a listener for the minecraft.Listener passed and starts accepting connections.
+func newListener(l *minecraft.Listener, v *Vers) *listener {
+	ln := &listener{Listener: l, v: v, incoming: make(chan *conn), closed: make(chan struct{})}
+	go ln.accept()
+	return ln
+}
+
+// accept accepts connections from the minecraft.Listener until it is closed, handling each of them in a new
+// goroutine.
+func (l *listener) accept() {
+	for {
+		netConn, err := l.Listener.Accept()
+		if err != nil {
+			l.err = err
+			close(l.closed)
+			return
+		}
+		go l.join(netConn.(*minecraft.Conn))
+	}
+}
+
+// join calls the Handler of the Vers instance for a connection that finished logging in and passes it on to
+// Accept, unless the join was cancelled.
+func (l *listener) join(mc *minecraft.Conn) {
+	id, err := uuid.Parse(mc.IdentityData().Identity)
 	if err != nil {
-		return nil, err
+		_ = l.Listener.Disconnect(mc, "Invalid identity.")
+		return
+	}
+
+	c := &conn{Conn: mc, id: id}
+	ctx, msg := event.C(), ""
+	l.v.handler().HandleJoin(ctx, c, c.Protocol(), &msg)
+	if ctx.Cancelled() {
+		_ = l.Listener.Disconnect(mc, msg)
+		return
+	}
+	registerConn(c)
+	select {
+	case l.incoming <- c:
+	case <-l.closed:
+		_ = c.Close()
+	}
+}
+
+// Accept accepts an incoming connection.
+func (l *listener) Accept() (session.Conn, error) {
+	select {
+	case c := <-l.incoming:
+		return c, nil
+	case <-l.closed:
+		return nil, l.err
 	}
-	return conn.(session.Conn), err
 }
 
 // Disconnect disconnects the connection with the given reason.
-func (l listener) Disconnect(conn session.Conn, reason string) error {
-	return l.Listener.Disconnect(conn.(*minecraft.Conn), reason)
+func (l *listener) Disconnect(c session.Conn, reason string) error {
+	if c, ok := c.(*conn); ok {
+		unregisterConn(c)
+		return l.Listener.Disconnect(c.Conn, reason)
+	}
+	return l.Listener.Disconnect(c.(*minecraft.Conn), reason)
+}
+
+// conn wraps around a *minecraft.Conn accepted by a listener, so that its protocol can be looked up for as long
+// as the connection is open.
+type conn struct {
+	*minecraft.Conn
+	id uuid.UUID
+}
+
+// Protocol returns the protocol the connection is communicating with, as passed when listening.
+func (c *conn) Protocol() minecraft.Protocol {
+	return unwrapProtocol(c.Conn.Protocol())
+}
+
+// Close closes the connection and removes it from the connections that can be looked up.
+func (c *conn) Close() error {
+	unregisterConn(c)
+	return c.Conn.Close()
 }
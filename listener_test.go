@@ -0,0 +1,66 @@
+package vers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/df-mc/dragonfly/server/session"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
+)
+
+// blockingHandler is a Handler that blocks the join of the player named "Slow" until release is closed.
+type blockingHandler struct {
+	NopHandler
+	release chan struct{}
+}
+
+func (h blockingHandler) HandleJoin(_ *event.Context, conn session.Conn, _ minecraft.Protocol, _ *string) {
+	if conn.IdentityData().DisplayName == "Slow" {
+		<-h.release
+	}
+}
+
+// TestListenerConcurrentJoins tests that a Handler blocking the join of one connection does not prevent other
+// connections from being accepted.
+func TestListenerConcurrentJoins(t *testing.T) {
+	l, err := minecraft.ListenConfig{AuthenticationDisabled: true}.Listen(LoopbackNetwork, "127.0.0.1:19140")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := New()
+	h := blockingHandler{release: make(chan struct{})}
+	v.Handle(h)
+	ln := newListener(l, v)
+	t.Cleanup(func() {
+		close(h.release)
+		_ = ln.Close()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	t.Cleanup(cancel)
+	dial := func(name string) {
+		_, _ = minecraft.Dialer{IdentityData: login.IdentityData{DisplayName: name}}.DialContext(ctx, LoopbackNetwork, "127.0.0.1:19140")
+	}
+	go dial("Slow")
+	// Give the first client a head start, so that its join is handled first.
+	time.Sleep(time.Millisecond * 200)
+	go dial("Fast")
+
+	accepted := make(chan session.Conn, 1)
+	go func() {
+		if c, err := ln.Accept(); err == nil {
+			accepted <- c
+		}
+	}()
+	select {
+	case c := <-accepted:
+		if name := c.IdentityData().DisplayName; name != "Fast" {
+			t.Errorf("expected Fast to be accepted first, got %v", name)
+		}
+	case <-ctx.Done():
+		t.Fatalf("no connection was accepted while the join of another connection was blocked")
+	}
+}
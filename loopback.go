@@ -0,0 +1,273 @@
+package vers
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sandertv/go-raknet"
+	"github.com/sandertv/gophertunnel/minecraft"
+)
+
+// LoopbackNetwork is the ID of an in-process network that may be used in place of "raknet", for example
+// using WithNetwork(LoopbackNetwork) and minecraft.Dialer.Dial(LoopbackNetwork, address). Connections over
+// the network never leave the process: RakNet datagrams are passed between listeners and dialers in memory,
+// so that servers and clients may be tested without binding to any real port.
+const LoopbackNetwork = "vers-loopback"
+
+func init() {
+	minecraft.RegisterNetwork(LoopbackNetwork, loopbackNetwork{})
+}
+
+// loopbackNetwork implements a minecraft.Network that runs RakNet over in-memory packet connections.
+type loopbackNetwork struct {
+	minecraft.RakNet
+}
+
+// DialContext ...
+func (loopbackNetwork) DialContext(ctx context.Context, address string) (net.Conn, error) {
+	return raknet.Dialer{UpstreamDialer: loopbackNetwork{}}.DialContext(ctx, address)
+}
+
+// PingContext ...
+func (loopbackNetwork) PingContext(ctx context.Context, address string) ([]byte, error) {
+	return raknet.Dialer{UpstreamDialer: loopbackNetwork{}}.PingContext(ctx, address)
+}
+
+// Listen ...
+func (loopbackNetwork) Listen(address string) (minecraft.NetworkListener, error) {
+	return raknet.ListenConfig{UpstreamPacketListener: loopbackNetwork{}}.Listen(address)
+}
+
+// Dial opens a packet connection to a loopback listener listening on the address passed.
+func (loopbackNetwork) Dial(_, address string) (net.Conn, error) {
+	remote, err := loopbackAddr(address)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := loopback.conn(remote); !ok {
+		return nil, &net.OpError{Op: "dial", Net: "udp", Addr: remote, Err: fmt.Errorf("no loopback listener")}
+	}
+	local, _ := loopbackAddr(":0")
+	return loopback.open(local, remote)
+}
+
+// ListenPacket opens a packet connection listening on the address passed.
+func (loopbackNetwork) ListenPacket(_, address string) (net.PacketConn, error) {
+	local, err := loopbackAddr(address)
+	if err != nil {
+		return nil, err
+	}
+	return loopback.open(local, nil)
+}
+
+// loopback holds all open loopback packet connections.
+var loopback = &loopbackConns{conns: map[string]*loopbackConn{}, port: 50000}
+
+// loopbackConns is a registry of loopback packet connections, indexed by their local address.
+type loopbackConns struct {
+	mu    sync.Mutex
+	conns map[string]*loopbackConn
+	port  int
+}
+
+// open opens a packet connection on the local address passed. If the port of the address is 0, a free port
+// is assigned.
+func (l *loopbackConns) open(local, remote *net.UDPAddr) (*loopbackConn, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if local.Port == 0 {
+		for {
+			l.port++
+			local.Port = l.port
+			if _, ok := l.conns[local.String()]; !ok {
+				break
+			}
+		}
+	}
+	if _, ok := l.conns[local.String()]; ok {
+		return nil, &net.OpError{Op: "listen", Net: "udp", Addr: local, Err: fmt.Errorf("address already in use")}
+	}
+	c := &loopbackConn{
+		local:    local,
+		remote:   remote,
+		incoming: make(chan loopbackDatagram, 4096),
+		closed:   make(chan struct{}),
+		deadline: make(chan struct{}),
+	}
+	l.conns[local.String()] = c
+	return c, nil
+}
+
+// conn looks up the packet connection with the local address passed.
+func (l *loopbackConns) conn(addr net.Addr) (*loopbackConn, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	c, ok := l.conns[addr.String()]
+	return c, ok
+}
+
+// remove removes the packet connection passed from the registry.
+func (l *loopbackConns) remove(c *loopbackConn) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.conns[c.local.String()] == c {
+		delete(l.conns, c.local.String())
+	}
+}
+
+// loopbackAddr resolves the address passed to a UDP address. Addresses without an IP resolve to 127.0.0.1.
+func loopbackAddr(address string) (*net.UDPAddr, error) {
+	addr, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return nil, err
+	}
+	if addr.IP == nil || addr.IP.IsUnspecified() {
+		addr.IP = net.IPv4(127, 0, 0, 1)
+	}
+	return addr, nil
+}
+
+// loopbackDatagram is a single datagram sent over a loopback packet connection.
+type loopbackDatagram struct {
+	data []byte
+	src  net.Addr
+}
+
+// loopbackConn is an in-memory packet connection. It implements both net.PacketConn and, if it has a remote
+// address, net.Conn. Like UDP, datagrams written to a connection that is closed or whose buffer is full are
+// dropped.
+type loopbackConn struct {
+	local, remote *net.UDPAddr
+	incoming      chan loopbackDatagram
+
+	once   sync.Once
+	closed chan struct{}
+
+	mu           sync.Mutex
+	readDeadline time.Time
+	// deadline is closed and replaced when the read deadline changes, so that blocked reads pick up the new
+	// deadline.
+	deadline chan struct{}
+}
+
+// ReadFrom ...
+func (c *loopbackConn) ReadFrom(b []byte) (int, net.Addr, error) {
+	for {
+		c.mu.Lock()
+		t, changed := c.readDeadline, c.deadline
+		c.mu.Unlock()
+
+		var (
+			timer   *time.Timer
+			timeout <-chan time.Time
+		)
+		if !t.IsZero() {
+			timer = time.NewTimer(time.Until(t))
+			timeout = timer.C
+		}
+		select {
+		case d := <-c.incoming:
+			stopTimer(timer)
+			return copy(b, d.data), d.src, nil
+		case <-c.closed:
+			stopTimer(timer)
+			return 0, nil, c.err("read", net.ErrClosed)
+		case <-timeout:
+			return 0, nil, c.err("read", os.ErrDeadlineExceeded)
+		case <-changed:
+			stopTimer(timer)
+		}
+	}
+}
+
+// WriteTo ...
+func (c *loopbackConn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	select {
+	case <-c.closed:
+		return 0, c.err("write", net.ErrClosed)
+	default:
+	}
+	dst, ok := loopback.conn(addr)
+	if !ok {
+		return len(b), nil
+	}
+	select {
+	case dst.incoming <- loopbackDatagram{data: append([]byte(nil), b...), src: c.local}:
+	default:
+	}
+	return len(b), nil
+}
+
+// Read ...
+func (c *loopbackConn) Read(b []byte) (int, error) {
+	n, _, err := c.ReadFrom(b)
+	return n, err
+}
+
+// Write ...
+func (c *loopbackConn) Write(b []byte) (int, error) {
+	if c.remote == nil {
+		return 0, c.err("write", fmt.Errorf("connection has no remote address"))
+	}
+	return c.WriteTo(b, c.remote)
+}
+
+// Close ...
+func (c *loopbackConn) Close() error {
+	c.once.Do(func() {
+		loopback.remove(c)
+		close(c.closed)
+	})
+	return nil
+}
+
+// LocalAddr ...
+func (c *loopbackConn) LocalAddr() net.Addr {
+	return c.local
+}
+
+// RemoteAddr ...
+func (c *loopbackConn) RemoteAddr() net.Addr {
+	if c.remote == nil {
+		return nil
+	}
+	return c.remote
+}
+
+// SetDeadline ...
+func (c *loopbackConn) SetDeadline(t time.Time) error {
+	_ = c.SetWriteDeadline(t)
+	return c.SetReadDeadline(t)
+}
+
+// SetReadDeadline ...
+func (c *loopbackConn) SetReadDeadline(t time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.readDeadline = t
+	close(c.deadline)
+	c.deadline = make(chan struct{})
+	return nil
+}
+
+// SetWriteDeadline is a no-op: Writes to a loopback connection never block.
+func (c *loopbackConn) SetWriteDeadline(time.Time) error {
+	return nil
+}
+
+// stopTimer stops the timer passed if it is non-nil.
+func stopTimer(t *time.Timer) {
+	if t != nil {
+		t.Stop()
+	}
+}
+
+// err wraps the error passed in a net.OpError.
+func (c *loopbackConn) err(op string, err error) error {
+	return &net.OpError{Op: op, Net: "udp", Source: c.local, Addr: c.RemoteAddr(), Err: err}
+}
@@ -0,0 +1,197 @@
+package vers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/df-mc/dragonfly/server"
+	"github.com/df-mc/dragonfly/server/item"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/go-gl/mathgl/mgl32"
+	"github.com/oomph-ac/mv/multiversion/chunk"
+	"github.com/oomph-ac/mv/multiversion/latest"
+	"github.com/oomph-ac/mv/multiversion/mv567"
+	"github.com/oomph-ac/mv/multiversion/mv568"
+	"github.com/oomph-ac/mv/multiversion/mv575"
+	"github.com/oomph-ac/mv/multiversion/mv582"
+	"github.com/oomph-ac/mv/multiversion/mv589"
+	"github.com/oomph-ac/mv/multiversion/mv594"
+	"github.com/oomph-ac/mv/multiversion/mv618"
+	"github.com/oomph-ac/mv/multiversion/mv622"
+	"github.com/oomph-ac/mv/multiversion/mv630"
+	"github.com/oomph-ac/mv/multiversion/mv649"
+	"github.com/oomph-ac/mv/multiversion/mv662"
+	"github.com/oomph-ac/mv/multiversion/util"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/sirupsen/logrus"
+)
+
+// TestLoopback starts a Dragonfly server through Vers on the LoopbackNetwork and joins it with a client of
+// every supported protocol. It checks that each client can log in, spawn, receive chunks and inventory
+// contents and move around.
+func TestLoopback(t *testing.T) {
+	protocols := []minecraft.Protocol{
+		mv567.Protocol{},
+		mv568.Protocol{},
+		mv575.Protocol{},
+		mv582.Protocol{},
+		mv589.Protocol{},
+		mv594.Protocol{},
+		mv618.Protocol{},
+		mv622.Protocol{},
+		mv630.Protocol{},
+		mv649.Protocol{},
+		mv662.Protocol{},
+	}
+
+	log := logrus.New()
+	log.Out = io.Discard
+
+	conf := server.Config{Log: log, Name: "Vers", AuthDisabled: true, DisableResourceBuilding: true}
+	v := New(":19132")
+	v.Listen(&conf, conf.Name, protocols, false, WithNetwork(LoopbackNetwork), PreloadMappings())
+
+	srv := conf.New()
+	srv.Listen()
+	t.Cleanup(func() {
+		_ = srv.Close()
+	})
+
+	players := make(chan *player.Player)
+	go func() {
+		for srv.Accept(func(p *player.Player) {
+			_, _ = p.Inventory().AddItem(item.NewStack(item.Diamond{}, 1))
+			players <- p
+		}) {
+		}
+	}()
+
+	for _, proto := range protocols {
+		proto := proto
+		t.Run(proto.Ver(), func(t *testing.T) {
+			testLoopbackJoin(t, proto, players)
+		})
+	}
+}
+
+// testLoopbackJoin joins the loopback server using the protocol passed and checks that the connection works
+// as expected.
+func testLoopbackJoin(t *testing.T, proto minecraft.Protocol, players <-chan *player.Player) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*30)
+	defer cancel()
+
+	conn, err := minecraft.Dialer{
+		Protocol:     proto,
+		IdentityData: login.IdentityData{DisplayName: fmt.Sprintf("Vers%v", proto.ID())},
+	}.DialContext(ctx, LoopbackNetwork, "127.0.0.1:19132")
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+
+	if err := conn.DoSpawnContext(ctx); err != nil {
+		t.Fatalf("spawn: %v", err)
+	}
+	if conn.GameData().WorldName != "Vers" {
+		t.Errorf("StartGame: expected world name %q, got %q", "Vers", conn.GameData().WorldName)
+	}
+
+	var p *player.Player
+	select {
+	case p = <-players:
+	case <-ctx.Done():
+		t.Fatalf("player was never accepted by the server")
+	}
+	if got, ok := PlayerProtocol(p); !ok || got.ID() != proto.ID() {
+		t.Errorf("expected server-side protocol %v, got %v (%v)", proto.ID(), got, ok)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second * 10))
+	var chunkRequested, chunkReceived, diamondReceived bool
+	for !chunkReceived || !diamondReceived {
+		pk, err := conn.ReadPacket()
+		if err != nil {
+			t.Fatalf("read packet (chunk = %v, diamond = %v): %v", chunkReceived, diamondReceived, err)
+		}
+		switch pk := pk.(type) {
+		case *packet.LevelChunk:
+			if chunkRequested || pk.SubChunkCount != protocol.SubChunkRequestModeLimited {
+				continue
+			}
+			// Dragonfly only sends sub chunks on request. Request the bottom sub chunks of the chunk, which
+			// hold the blocks of the flat world.
+			offsets := make([]protocol.SubChunkOffset, 0, 4)
+			for y := int8(-4); y < 0; y++ {
+				offsets = append(offsets, protocol.SubChunkOffset{0, y, 0})
+			}
+			if err := conn.WritePacket(&packet.SubChunkRequest{
+				Position: protocol.SubChunkPos{pk.Position[0], 0, pk.Position[1]},
+				Offsets:  offsets,
+			}); err != nil {
+				t.Fatalf("write SubChunkRequest: %v", err)
+			}
+			chunkRequested = true
+		case *packet.SubChunk:
+			for _, entry := range pk.SubChunkEntries {
+				if entry.Result != protocol.SubChunkResultSuccess {
+					continue
+				}
+				var index byte
+				sub, err := chunk.DecodeSubChunk(util.LatestAirRID(), world.Overworld.Range(), bytes.NewBuffer(entry.RawPayload), &index, chunk.NetworkEncoding)
+				if err != nil {
+					t.Fatalf("decode sub chunk: %v", err)
+				}
+				chunkReceived = chunkReceived || subChunkHasBlocks(sub)
+			}
+		case *packet.InventoryContent:
+			for _, it := range pk.Content {
+				diamondReceived = diamondReceived || isDiamond(it.Stack)
+			}
+		case *packet.InventorySlot:
+			diamondReceived = diamondReceived || isDiamond(pk.NewItem.Stack)
+		}
+	}
+
+	start := p.Position()
+	pos := conn.GameData().PlayerPosition.Add(mgl32.Vec3{0.5})
+	for tick := uint64(0); tick < 100; tick++ {
+		if err := conn.WritePacket(&packet.PlayerAuthInput{Position: pos, Tick: tick, InputMode: packet.InputModeMouse}); err != nil {
+			t.Fatalf("write PlayerAuthInput: %v", err)
+		}
+		if p.Position().Sub(start).Len() > 0.4 {
+			return
+		}
+		time.Sleep(time.Millisecond * 50)
+	}
+	t.Errorf("player never moved: started at %v, now at %v", start, p.Position())
+}
+
+// subChunkHasBlocks checks if the sub chunk passed has any non-air blocks.
+func subChunkHasBlocks(sub *chunk.SubChunk) bool {
+	for _, layer := range sub.Layers() {
+		for x := uint8(0); x < 16; x++ {
+			for y := uint8(0); y < 16; y++ {
+				for z := uint8(0); z < 16; z++ {
+					if layer.At(x, y, z) != util.LatestAirRID() {
+						return true
+					}
+				}
+			}
+		}
+	}
+	return false
+}
+
+// isDiamond checks if the item stack passed is a diamond of the latest version.
+func isDiamond(s protocol.ItemStack) bool {
+	name, ok := latest.ItemRuntimeIDToName(s.NetworkID)
+	return ok && name == "minecraft:diamond"
+}
@@ -0,0 +1,102 @@
+package capability
+
+import (
+	"reflect"
+
+	"github.com/oomph-ac/mv/multiversion/mappings"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Capability is a feature of the game that a client may or may not support, depending on its protocol.
+type Capability uint
+
+const (
+	// Crafter is the crafter block, present in the block palette since 1.20.50.
+	Crafter Capability = iota
+	// HangingSigns are the hanging sign blocks.
+	HangingSigns
+	// SetHud is the SetHud packet, used to hide or show elements of the HUD.
+	SetHud
+	// CameraPresets is the CameraPresets packet, used to register camera presets.
+	CameraPresets
+	// ClientBoundDebugRenderer is the ClientBoundDebugRenderer packet, used to render debug shapes.
+	ClientBoundDebugRenderer
+	// VehicleRotation is the vehicle rotation sent by the client in the PlayerAuthInput packet.
+	VehicleRotation
+
+	// count is the amount of capabilities that exist.
+	count
+)
+
+// names holds the names of all capabilities, indexed by the capability.
+var names = [...]string{
+	Crafter:                  "crafter",
+	HangingSigns:             "hanging_signs",
+	SetHud:                   "set_hud",
+	CameraPresets:            "camera_presets",
+	ClientBoundDebugRenderer: "client_bound_debug_renderer",
+	VehicleRotation:          "vehicle_rotation",
+}
+
+// String returns the name of the capability.
+func (c Capability) String() string {
+	if c >= count {
+		return "unknown"
+	}
+	return names[c]
+}
+
+// Set is a set of capabilities supported by a protocol.
+type Set uint64
+
+// All is a Set holding every capability. It is the Set of the latest protocol.
+const All = Set(1<<count - 1)
+
+// Has checks if the Set holds the Capability passed.
+func (s Set) Has(c Capability) bool {
+	return s&(1<<c) != 0
+}
+
+// Capabilities returns all capabilities held by the Set.
+func (s Set) Capabilities() []Capability {
+	var capabilities []Capability
+	for c := Capability(0); c < count; c++ {
+		if s.Has(c) {
+			capabilities = append(capabilities, c)
+		}
+	}
+	return capabilities
+}
+
+// Derive derives the Set of a protocol from the packet pools and mapping that are used to translate its
+// packets. The server pool holds packets sent by the server, the client pool holds packets sent by the client.
+func Derive(server, client packet.Pool, mapping mappings.MVMapping) Set {
+	var s Set
+	for _, b := range mapping.Blocks() {
+		switch b.Name {
+		case "minecraft:crafter":
+			s |= 1 << Crafter
+		case "minecraft:oak_hanging_sign":
+			s |= 1 << HangingSigns
+		}
+	}
+	if _, ok := server[packet.IDSetHud]; ok {
+		s |= 1 << SetHud
+	}
+	if _, ok := server[packet.IDCameraPresets]; ok {
+		s |= 1 << CameraPresets
+	}
+	if _, ok := server[packet.IDClientBoundDebugRenderer]; ok {
+		s |= 1 << ClientBoundDebugRenderer
+	}
+	if f, ok := client[packet.IDPlayerAuthInput]; ok && hasField(f(), "VehicleRotation") {
+		s |= 1 << VehicleRotation
+	}
+	return s
+}
+
+// hasField checks if the packet passed has a field with the name passed.
+func hasField(pk packet.Packet, name string) bool {
+	_, ok := reflect.TypeOf(pk).Elem().FieldByName(name)
+	return ok
+}
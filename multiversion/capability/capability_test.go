@@ -0,0 +1,41 @@
+package capability_test
+
+import (
+	"testing"
+
+	"github.com/oomph-ac/mv/multiversion/capability"
+	"github.com/oomph-ac/mv/multiversion/mv568"
+	"github.com/oomph-ac/mv/multiversion/mv575"
+	"github.com/oomph-ac/mv/multiversion/mv589"
+	"github.com/oomph-ac/mv/multiversion/mv630"
+	"github.com/oomph-ac/mv/multiversion/mv649"
+	"github.com/oomph-ac/mv/multiversion/mv662"
+)
+
+// TestDerive tests that the capabilities of protocols follow the packets and blocks they translate.
+func TestDerive(t *testing.T) {
+	tests := []struct {
+		name     string
+		set      capability.Set
+		has, not []capability.Capability
+	}{
+		{"1.19.63", mv568.Protocol{}.Capabilities(), nil, []capability.Capability{capability.Crafter, capability.CameraPresets, capability.SetHud}},
+		{"1.19.70", mv575.Protocol{}.Capabilities(), []capability.Capability{capability.CameraPresets}, []capability.Capability{capability.Crafter, capability.SetHud}},
+		{"1.20.0", mv589.Protocol{}.Capabilities(), []capability.Capability{capability.HangingSigns}, []capability.Capability{capability.Crafter, capability.SetHud, capability.VehicleRotation}},
+		{"1.20.50", mv630.Protocol{}.Capabilities(), []capability.Capability{capability.Crafter}, []capability.Capability{capability.SetHud, capability.VehicleRotation}},
+		{"1.20.60", mv649.Protocol{}.Capabilities(), []capability.Capability{capability.Crafter, capability.SetHud}, []capability.Capability{capability.VehicleRotation}},
+		{"1.20.70", mv662.Protocol{}.Capabilities(), []capability.Capability{capability.Crafter, capability.SetHud, capability.VehicleRotation}, nil},
+	}
+	for _, test := range tests {
+		for _, c := range test.has {
+			if !test.set.Has(c) {
+				t.Errorf("%v: expected capability %v", test.name, c)
+			}
+		}
+		for _, c := range test.not {
+			if test.set.Has(c) {
+				t.Errorf("%v: unexpected capability %v", test.name, c)
+			}
+		}
+	}
+}
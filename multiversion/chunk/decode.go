@@ -11,19 +11,23 @@ import (
 // The sub chunk count passed must be that found in the LevelChunk packet.
 // noinspection GoUnusedExportedFunction
 func NetworkDecode(air uint32, buf *bytes.Buffer, count int, oldFormat bool, r cube.Range) (*Chunk, error) {
-	var (
-		c   = New(air, r)
-		err error
-	)
+	c := New(air, r)
+	if count < 0 || count > len(c.sub) {
+		return nil, fmt.Errorf("invalid sub chunk count %v: chunk holds at most %v sub chunks", count, len(c.sub))
+	}
 	for i := 0; i < count; i++ {
 		index := uint8(i)
 		if oldFormat {
 			index += 4
 		}
-		c.sub[index], err = DecodeSubChunk(air, r, buf, &index, NetworkEncoding)
+		sub, err := DecodeSubChunk(air, r, buf, &index, NetworkEncoding)
 		if err != nil {
 			return nil, err
 		}
+		if int(index) >= len(c.sub) {
+			return nil, fmt.Errorf("sub chunk index %v out of range: chunk holds %v sub chunks", index, len(c.sub))
+		}
+		c.sub[index] = sub
 	}
 	if oldFormat {
 		// Read the old biomes.
@@ -80,7 +84,7 @@ func DecodeSubChunk(air uint32, r cube.Range, buf *bytes.Buffer, index *byte, e
 		return nil, fmt.Errorf("unknown sub chunk version %v: can't decode", ver)
 	case 1:
 		// Version 1 only has one layer for each sub chunk, but uses the format with palettes.
-		storage, err := decodePalettedStorage(buf, e, BlockPaletteEncoding)
+		storage, err := decodeBlockStorage(buf, e)
 		if err != nil {
 			return nil, err
 		}
@@ -103,7 +107,7 @@ func DecodeSubChunk(air uint32, r cube.Range, buf *bytes.Buffer, index *byte, e
 		sub.storages = make([]*PalettedStorage, storageCount)
 
 		for i := byte(0); i < storageCount; i++ {
-			sub.storages[i], err = decodePalettedStorage(buf, e, BlockPaletteEncoding)
+			sub.storages[i], err = decodeBlockStorage(buf, e)
 			if err != nil {
 				return nil, err
 			}
@@ -112,6 +116,16 @@ func DecodeSubChunk(air uint32, r cube.Range, buf *bytes.Buffer, index *byte, e
 	return sub, nil
 }
 
+// decodeBlockStorage decodes a PalettedStorage holding blocks from a bytes.Buffer. Unlike biome storages, block
+// storages can't point to a previous storage.
+func decodeBlockStorage(buf *bytes.Buffer, e Encoding) (*PalettedStorage, error) {
+	storage, err := decodePalettedStorage(buf, e, BlockPaletteEncoding)
+	if err == nil && storage == nil {
+		return nil, fmt.Errorf("block storage pointed to previous one")
+	}
+	return storage, err
+}
+
 // decodePalettedStorage decodes a PalettedStorage from a bytes.Buffer. The Encoding passed is used to read either a
 // network or disk block storage.
 func decodePalettedStorage(buf *bytes.Buffer, e Encoding, pe paletteEncoding) (*PalettedStorage, error) {
@@ -129,6 +143,9 @@ func decodePalettedStorage(buf *bytes.Buffer, e Encoding, pe paletteEncoding) (*
 	}
 
 	size := paletteSize(blockSize)
+	if !size.valid() {
+		return nil, fmt.Errorf("invalid paletted storage size %v", blockSize)
+	}
 	uint32Count := size.uint32s()
 
 	uint32s := make([]uint32, uint32Count)
@@ -143,5 +160,12 @@ func decodePalettedStorage(buf *bytes.Buffer, e Encoding, pe paletteEncoding) (*
 		uint32s[i] = uint32(data[i*4]) | uint32(data[i*4+1])<<8 | uint32(data[i*4+2])<<16 | uint32(data[i*4+3])<<24
 	}
 	p, err := e.decodePalette(buf, paletteSize(blockSize), pe)
-	return newPalettedStorage(uint32s, p), err
+	if err != nil {
+		return nil, err
+	}
+	storage := newPalettedStorage(uint32s, p)
+	if err := storage.checkIndices(); err != nil {
+		return nil, err
+	}
+	return storage, nil
 }
@@ -0,0 +1,86 @@
+package chunk
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/df-mc/dragonfly/server/world"
+)
+
+// FuzzNetworkDecode fuzzes the decoding of the payload of a LevelChunk packet. Decoding must either fail or
+// return a chunk of which every block can be read.
+func FuzzNetworkDecode(f *testing.F) {
+	r := world.Overworld.Range()
+	c := testChunk()
+	data := Encode(c, NetworkEncoding, r)
+	payload := bytes.NewBuffer(nil)
+	for _, sub := range data.SubChunks {
+		payload.Write(sub)
+	}
+	payload.Write(data.Biomes)
+	f.Add(payload.Bytes(), uint8(len(data.SubChunks)), false)
+	f.Add(payload.Bytes(), uint8(len(data.SubChunks)+1), false)
+	f.Add(payload.Bytes(), uint8(4), true)
+
+	f.Fuzz(func(t *testing.T, payload []byte, count uint8, oldFormat bool) {
+		c, err := NetworkDecode(0, bytes.NewBuffer(payload), int(count), oldFormat, r)
+		if err != nil {
+			return
+		}
+		for _, sub := range c.Sub() {
+			readAll(sub)
+		}
+		for x := uint8(0); x < 16; x++ {
+			for z := uint8(0); z < 16; z++ {
+				_ = c.Biome(x, int16(r.Min()), z)
+			}
+		}
+	})
+}
+
+// FuzzDecodeSubChunk fuzzes the decoding of a sub chunk in the payload of a SubChunk packet. Decoding must either
+// fail or return a sub chunk of which every block can be read.
+func FuzzDecodeSubChunk(f *testing.F) {
+	r := world.Overworld.Range()
+	c := testChunk()
+	f.Add(EncodeSubChunk(c.Sub()[0], NetworkEncoding, r, 0))
+	f.Add(EncodeSubChunk(c.Sub()[1], NetworkEncoding, r, 1))
+	f.Add(EncodeSubChunk(NewSubChunk(0), NetworkEncoding, r, 2))
+	// A block storage pointing to the previous storage, which only biome storages may do.
+	f.Add([]byte("\t\x010\xff"))
+
+	f.Fuzz(func(t *testing.T, payload []byte) {
+		var index byte
+		sub, err := DecodeSubChunk(0, r, bytes.NewBuffer(payload), &index, NetworkEncoding)
+		if err != nil {
+			return
+		}
+		readAll(sub)
+	})
+}
+
+// testChunk returns a chunk with blocks in its first two sub chunks, which use different palette sizes.
+func testChunk() *Chunk {
+	r := world.Overworld.Range()
+	c := New(0, r)
+	for x := uint8(0); x < 16; x++ {
+		for z := uint8(0); z < 16; z++ {
+			c.SetBlock(x, int16(r.Min()), z, 0, 1)
+			c.SetBlock(x, int16(r.Min())+16, z, 0, uint32(x)*16+uint32(z))
+		}
+	}
+	return c
+}
+
+// readAll reads every block in every layer of the sub chunk passed.
+func readAll(sub *SubChunk) {
+	for _, layer := range sub.Layers() {
+		for x := uint8(0); x < 16; x++ {
+			for y := uint8(0); y < 16; y++ {
+				for z := uint8(0); z < 16; z++ {
+					_ = layer.At(x, y, z)
+				}
+			}
+		}
+	}
+}
@@ -98,7 +98,7 @@ func (networkEncoding) decodePalette(buf *bytes.Buffer, blockSize paletteSize, _
 		if err := protocol.Varint32(buf, &paletteCount); err != nil {
 			return nil, fmt.Errorf("error reading palette entry count: %w", err)
 		}
-		if paletteCount <= 0 {
+		if paletteCount <= 0 || paletteCount > 1<<blockSize || paletteCount > 4096 {
 			return nil, fmt.Errorf("invalid palette entry count %v", paletteCount)
 		}
 	}
@@ -132,11 +132,10 @@ func (networkPersistentEncoding) encodePalette(buf *bytes.Buffer, p *Palette, _
 func (networkPersistentEncoding) decodePalette(buf *bytes.Buffer, blockSize paletteSize, _ paletteEncoding) (*Palette, error) {
 	var paletteCount int32 = 1
 	if blockSize != 0 {
-		err := protocol.Varint32(buf, &paletteCount)
-		if err != nil {
-			panic(err)
+		if err := protocol.Varint32(buf, &paletteCount); err != nil {
+			return nil, fmt.Errorf("error reading palette entry count: %w", err)
 		}
-		if paletteCount <= 0 {
+		if paletteCount <= 0 || paletteCount > 1<<blockSize || paletteCount > 4096 {
 			return nil, fmt.Errorf("invalid palette entry count %v", paletteCount)
 		}
 	}
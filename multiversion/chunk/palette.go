@@ -112,6 +112,16 @@ func paletteSizeFor(n int) paletteSize {
 	return 0
 }
 
+// valid checks if the paletteSize is one of the sizes a palette may have.
+func (p paletteSize) valid() bool {
+	for _, size := range sizes {
+		if p == size {
+			return true
+		}
+	}
+	return false
+}
+
 // uint32s returns the amount of uint32s needed to represent a storage with this palette size.
 func (p paletteSize) uint32s() (n int) {
 	uint32Count := 0
@@ -1,6 +1,7 @@
 package chunk
 
 import (
+	"fmt"
 	"reflect"
 	"unsafe"
 )
@@ -120,6 +121,26 @@ func (storage *PalettedStorage) setPaletteIndex(x, y, z byte, i uint16) {
 	*ptr = (*ptr &^ (storage.indexMask << bitOffset)) | (uint32(i) << bitOffset)
 }
 
+// checkIndices checks if all indices in the PalettedStorage point to a value in its Palette. Storages decoded
+// from untrusted data may hold indices beyond the end of the Palette, which would otherwise panic once read.
+func (storage *PalettedStorage) checkIndices() error {
+	n := storage.palette.Len()
+	if n >= 1<<storage.bitsPerIndex {
+		// Every index that fits in bitsPerIndex points to a value.
+		return nil
+	}
+	for x := byte(0); x < 16; x++ {
+		for y := byte(0); y < 16; y++ {
+			for z := byte(0); z < 16; z++ {
+				if i := storage.paletteIndex(x, y, z); int(i) >= n {
+					return fmt.Errorf("palette index %v out of range: palette holds %v values", i, n)
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // resize changes the size of a PalettedStorage to newPaletteSize. A new PalettedStorage is constructed,
 // and all values available in the current storage are set in their appropriate locations in the
 // new storage.
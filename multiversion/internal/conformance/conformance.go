@@ -0,0 +1,325 @@
+// Package conformance implements a round-trip test suite for the packets overridden by a multi-version
+// protocol. For every packet a protocol decodes differently from the latest version, a latest packet is
+// populated using reflection, converted to the protocol, encoded and decoded using its pools and converted
+// back to the latest version. Fields that exist in both versions must survive the round trip.
+package conformance
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/oomph-ac/mv/multiversion/latest"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// Test runs the round-trip test suite for all packets overridden by the protocol passed. The packets that a
+// protocol overrides are found by comparing its pools with those of the latest version.
+func Test(t *testing.T, proto minecraft.Protocol) {
+	for _, fromClient := range []bool{true, false} {
+		pool, latestPool := proto.Packets(fromClient), packet.NewServerPool()
+		if fromClient {
+			latestPool = packet.NewClientPool()
+		}
+		ids := make([]uint32, 0, len(pool))
+		for id, f := range pool {
+			if l, ok := latestPool[id]; ok && reflect.TypeOf(f()) != reflect.TypeOf(l()) {
+				ids = append(ids, id)
+			}
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+		for _, id := range ids {
+			variants, ok := sanitisers[id]
+			if !ok {
+				variants = []func(pk packet.Packet){func(packet.Packet) {}}
+			}
+			for i, sanitise := range variants {
+				t.Run(fmt.Sprintf("%T#%v", latestPool[id](), i), func(t *testing.T) {
+					roundTrip(t, proto, pool, func() packet.Packet {
+						pk := latestPool[id]()
+						Fill(pk)
+						sanitise(pk)
+						return pk
+					})
+				})
+			}
+		}
+	}
+}
+
+// roundTrip converts a packet returned by pk to the protocol passed, encodes it, decodes it using the pool
+// passed and converts it back to the latest version. It then compares the result with a fresh packet
+// returned by pk.
+func roundTrip(t *testing.T, proto minecraft.Protocol, pool packet.Pool, pk func() packet.Packet) {
+	want, conn := pk(), &minecraft.Conn{}
+
+	legacy := proto.ConvertFromLatest(pk(), conn)
+	if len(legacy) != 1 {
+		t.Fatalf("expected 1 packet after downgrading, got %v", len(legacy))
+	}
+	if check, ok := legacyChecks[want.ID()]; ok {
+		for _, err := range check(want, legacy[0]) {
+			t.Error(err)
+		}
+	}
+	buf := bytes.NewBuffer(nil)
+	legacy[0].Marshal(proto.NewWriter(buf, 0))
+
+	f, ok := pool[legacy[0].ID()]
+	if !ok {
+		t.Fatalf("packet %v not in pool", legacy[0].ID())
+	}
+	decoded := f()
+	if reflect.TypeOf(decoded) != reflect.TypeOf(legacy[0]) {
+		t.Fatalf("downgraded to %T, but pool decodes %T", legacy[0], decoded)
+	}
+	decoded.Marshal(proto.NewReader(buf, 0, false))
+	if buf.Len() != 0 {
+		t.Errorf("%v bytes left after decoding %T", buf.Len(), decoded)
+	}
+
+	upgraded := proto.ConvertToLatest(decoded, conn)
+	if len(upgraded) != 1 {
+		t.Fatalf("expected 1 packet after upgrading, got %v", len(upgraded))
+	}
+	if reflect.TypeOf(upgraded[0]) != reflect.TypeOf(want) {
+		t.Fatalf("upgraded to %T, expected %T", upgraded[0], want)
+	}
+	if _, ok := lossy[want.ID()]; ok {
+		return
+	}
+	for _, diff := range compare(reflect.ValueOf(want).Elem(), reflect.ValueOf(upgraded[0]).Elem(), reflect.ValueOf(legacy[0]).Elem(), "") {
+		t.Error(diff)
+	}
+}
+
+// compare compares the values want and got field by field and returns a description of every difference.
+// Struct fields are only compared if a field with the same name exists in the legacy value, which is the
+// value want was converted to before being converted back into got.
+func compare(want, got, legacy reflect.Value, path string) []string {
+	if legacy.Kind() == reflect.Interface && !legacy.IsNil() {
+		legacy = legacy.Elem()
+	}
+	if legacy.Kind() == reflect.Pointer && !legacy.IsNil() {
+		legacy = legacy.Elem()
+	}
+	switch want.Kind() {
+	case reflect.Struct:
+		if legacy.Kind() != reflect.Struct || !hasExportedFields(want.Type()) {
+			// Structs without exported fields, such as protocol.Optional, are compared as a whole.
+			break
+		}
+		var diffs []string
+		for i := 0; i < want.NumField(); i++ {
+			field := want.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			legacyField := legacy.FieldByName(field.Name)
+			if !legacyField.IsValid() {
+				// The field does not exist in the legacy version, so it can't be expected to survive.
+				continue
+			}
+			diffs = append(diffs, compare(want.Field(i), got.Field(i), legacyField, path+"."+field.Name)...)
+		}
+		return diffs
+	case reflect.Slice, reflect.Array:
+		if legacy.Kind() != reflect.Slice && legacy.Kind() != reflect.Array {
+			break
+		}
+		if want.Len() != legacy.Len() {
+			// The legacy version holds a different number of elements, so the slices are compared as a whole.
+			break
+		}
+		if want.Len() != got.Len() {
+			return []string{fmt.Sprintf("%v: expected length %v, got %v", path, want.Len(), got.Len())}
+		}
+		var diffs []string
+		for i := 0; i < want.Len(); i++ {
+			diffs = append(diffs, compare(want.Index(i), got.Index(i), legacy.Index(i), fmt.Sprintf("%v[%v]", path, i))...)
+		}
+		return diffs
+	case reflect.Map:
+		if want.Len() == 0 && got.Len() == 0 {
+			// Nil and empty maps are encoded the same way.
+			return nil
+		}
+	}
+	if !reflect.DeepEqual(want.Interface(), got.Interface()) {
+		return []string{fmt.Sprintf("%v: expected %v, got %v", path, want.Interface(), got.Interface())}
+	}
+	return nil
+}
+
+// hasExportedFields checks if the struct type passed has any exported fields.
+func hasExportedFields(t reflect.Type) bool {
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() {
+			return true
+		}
+	}
+	return false
+}
+
+// lossy holds the IDs of packets that are deliberately not converted losslessly. These packets are checked
+// to encode and decode correctly, but their fields are not compared.
+var lossy = map[uint32]struct{}{
+	// CraftingData is replaced with a packet that clears all recipes by util.DefaultDowngrade.
+	packet.IDCraftingData: {},
+}
+
+// commandParameterTypes holds a command parameter type for every argument type of the latest version, and
+// parameter types pointing to an enum, soft enum and suffix. The indices of the latter are argument types too,
+// so that they are changed if they are mistakenly translated as argument types.
+var commandParameterTypes = []uint32{
+	protocol.CommandArgValid | protocol.CommandArgTypeInt,
+	protocol.CommandArgValid | protocol.CommandArgTypeFloat,
+	protocol.CommandArgValid | protocol.CommandArgTypeValue,
+	protocol.CommandArgValid | protocol.CommandArgTypeWildcardInt,
+	protocol.CommandArgValid | protocol.CommandArgTypeOperator,
+	protocol.CommandArgValid | protocol.CommandArgTypeCompareOperator,
+	protocol.CommandArgValid | protocol.CommandArgTypeTarget,
+	protocol.CommandArgValid | protocol.CommandArgTypeWildcardTarget,
+	protocol.CommandArgValid | protocol.CommandArgTypeFilepath,
+	protocol.CommandArgValid | protocol.CommandArgTypeIntegerRange,
+	protocol.CommandArgValid | protocol.CommandArgTypeEquipmentSlots,
+	protocol.CommandArgValid | protocol.CommandArgTypeString,
+	protocol.CommandArgValid | protocol.CommandArgTypeBlockPosition,
+	protocol.CommandArgValid | protocol.CommandArgTypePosition,
+	protocol.CommandArgValid | protocol.CommandArgTypeMessage,
+	protocol.CommandArgValid | protocol.CommandArgTypeRawText,
+	protocol.CommandArgValid | protocol.CommandArgTypeJSON,
+	protocol.CommandArgValid | protocol.CommandArgTypeBlockStates,
+	protocol.CommandArgValid | protocol.CommandArgTypeCommand,
+	protocol.CommandArgValid | protocol.CommandArgEnum | protocol.CommandArgTypeString,
+	protocol.CommandArgValid | protocol.CommandArgSoftEnum | protocol.CommandArgTypeString,
+	protocol.CommandArgSuffixed | protocol.CommandArgTypeString,
+}
+
+// legacyChecks holds, for packets that need it, functions that check the packet converted to a protocol, before
+// it is converted back. They catch translations that are undone by the conversion back to the latest version.
+var legacyChecks = map[uint32]func(want, legacy packet.Packet) []string{
+	packet.IDAvailableCommands: func(want, legacy packet.Packet) []string {
+		legacyCommands, ok := reflect.ValueOf(legacy).Elem().FieldByName("Commands").Interface().([]protocol.Command)
+		if !ok {
+			return []string{fmt.Sprintf("%T: expected commands of type []protocol.Command", legacy)}
+		}
+		var diffs []string
+		for i, c := range want.(*packet.AvailableCommands).Commands {
+			for j, o := range c.Overloads {
+				for k, p := range o.Parameters {
+					if p.Type&(protocol.CommandArgEnum|protocol.CommandArgSoftEnum|protocol.CommandArgSuffixed) == 0 {
+						continue
+					}
+					if got := legacyCommands[i].Overloads[j].Parameters[k].Type; got != p.Type {
+						diffs = append(diffs, fmt.Sprintf(".Commands[%v].Overloads[%v].Parameters[%v].Type: expected %#x to be sent unchanged, got %#x", i, j, k, p.Type, got))
+					}
+				}
+			}
+		}
+		return diffs
+	},
+}
+
+// sanitisers holds, for packets that need it, functions that turn filled packets into packets that are valid
+// to encode, for example by setting fields that determine which other fields are encoded. Every function is
+// tested as a separate variant of the packet.
+var sanitisers = map[uint32][]func(pk packet.Packet){
+	packet.IDAvailableCommands: {func(pk packet.Packet) {
+		commands := pk.(*packet.AvailableCommands)
+		commands.EnumValues, commands.ChainedSubcommandValues, commands.Suffixes = nil, nil, nil
+		commands.Enums, commands.ChainedSubcommands = nil, nil
+		commands.DynamicEnums, commands.Constraints = nil, nil
+		for i := range commands.Commands {
+			commands.Commands[i].AliasesOffset = 0xffffffff
+			commands.Commands[i].ChainedSubcommandOffsets = nil
+			for j := range commands.Commands[i].Overloads {
+				// Chained subcommands are left out, as they are not sent to clients before 1.20.10.
+				commands.Commands[i].Overloads[j].Chaining = false
+				// The overload gets a parameter of every type, keeping the other fields of the filled parameter.
+				param := commands.Commands[i].Overloads[j].Parameters[0]
+				params := make([]protocol.CommandParameter, len(commandParameterTypes))
+				for k, t := range commandParameterTypes {
+					params[k], params[k].Type = param, t
+				}
+				commands.Commands[i].Overloads[j].Parameters = params
+			}
+		}
+	}},
+	packet.IDClientBoundDebugRenderer: {
+		func(pk packet.Packet) {
+			pk.(*packet.ClientBoundDebugRenderer).Type = packet.ClientBoundDebugRendererAddCube
+		},
+		func(pk packet.Packet) {
+			*pk.(*packet.ClientBoundDebugRenderer) = packet.ClientBoundDebugRenderer{Type: packet.ClientBoundDebugRendererClear}
+		},
+	},
+	packet.IDCraftingData: {func(pk packet.Packet) {
+		pk.(*packet.CraftingData).Recipes = nil
+	}},
+	packet.IDDisconnect: {func(pk packet.Packet) {
+		pk.(*packet.Disconnect).HideDisconnectionScreen = false
+	}},
+	packet.IDLevelChunk: {func(pk packet.Packet) {
+		pk.(*packet.LevelChunk).SubChunkCount = protocol.SubChunkRequestModeLimited
+	}},
+	packet.IDPlayerAuthInput: {func(pk packet.Packet) {
+		input := pk.(*packet.PlayerAuthInput)
+		input.InputData, input.PlayMode, input.GazeDirection = 0, packet.PlayModeNormal, [3]float32{}
+		input.ItemInteractionData = protocol.UseItemTransactionData{}
+		input.ItemStackRequest = protocol.ItemStackRequest{}
+		input.BlockActions = nil
+		input.VehicleRotation, input.ClientPredictedVehicle = [2]float32{}, 0
+	}},
+	packet.IDPlayerList: {
+		func(pk packet.Packet) {
+			list := pk.(*packet.PlayerList)
+			list.ActionType = packet.PlayerListActionAdd
+			for i := range list.Entries {
+				list.Entries[i].Skin = protocol.Skin{SkinID: "skin", SkinImageWidth: 1, SkinImageHeight: 1, SkinData: []byte{1, 2, 3, 4}, Trusted: true}
+			}
+		},
+		func(pk packet.Packet) {
+			list := pk.(*packet.PlayerList)
+			list.ActionType = packet.PlayerListActionRemove
+			for i, entry := range list.Entries {
+				// Only the UUID of an entry is sent when removing players.
+				list.Entries[i] = protocol.PlayerListEntry{UUID: entry.UUID}
+			}
+		},
+	},
+	packet.IDPlayerSkin: {func(pk packet.Packet) {
+		pk.(*packet.PlayerSkin).Skin = protocol.Skin{SkinID: "skin", SkinImageWidth: 1, SkinImageHeight: 1, SkinData: []byte{1, 2, 3, 4}, Trusted: true}
+	}},
+	packet.IDStartGame: {func(pk packet.Packet) {
+		start := pk.(*packet.StartGame)
+		start.EditorWorldType = packet.EditorWorldTypeProject
+		start.GameRules, start.Blocks, start.PropertyData = nil, nil, nil
+		start.ForceExperimentalGameplay = protocol.Option(true)
+		// The item table is rebuilt from the item table of each version, so only the complete item table of the
+		// latest version survives a round trip.
+		start.Items = latest.ItemEntries()
+	}},
+	packet.IDUnlockedRecipes: {
+		func(pk packet.Packet) {
+			pk.(*packet.UnlockedRecipes).UnlockType = packet.UnlockedRecipesTypeInitiallyUnlocked
+		},
+		func(pk packet.Packet) {
+			pk.(*packet.UnlockedRecipes).UnlockType = packet.UnlockedRecipesTypeNewlyUnlocked
+		},
+	},
+	packet.IDUpdateBlockSynced: {func(pk packet.Packet) {
+		pk.(*packet.UpdateBlockSynced).NewBlockRuntimeID = bedrockRuntimeID()
+	}},
+}
+
+// bedrockRuntimeID returns the runtime ID of bedrock in the latest version.
+func bedrockRuntimeID() uint32 {
+	rid, _ := latest.StateToRuntimeID("minecraft:bedrock", map[string]any{"infiniburn_bit": uint8(0)})
+	return rid
+}
@@ -0,0 +1,61 @@
+package conformance
+
+import (
+	"reflect"
+)
+
+// Fill populates all exported fields of the value pointed to by v with deterministic, non-zero values. Slices
+// and maps are filled with a single element. Interfaces, functions and channels are left untouched.
+func Fill(v any) {
+	var n uint64
+	fill(reflect.ValueOf(v).Elem(), &n, 0)
+}
+
+// fill populates v recursively, using n as a counter to make sure values that are filled are distinct.
+func fill(v reflect.Value, n *uint64, depth int) {
+	if depth > 8 {
+		// Stop filling recursive types such as NBT trees.
+		return
+	}
+	*n++
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(*n % 100))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v.SetUint(*n % 100)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(float64(*n%100) + 0.5)
+	case reflect.String:
+		v.SetString("s" + string(rune('a'+*n%26)))
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			fill(v.Index(i), n, depth+1)
+		}
+	case reflect.Slice:
+		s := reflect.MakeSlice(v.Type(), 1, 1)
+		fill(s.Index(0), n, depth+1)
+		v.Set(s)
+	case reflect.Map:
+		if v.Type().Key().Kind() == reflect.Interface || v.Type().Elem().Kind() == reflect.Interface {
+			return
+		}
+		m := reflect.MakeMap(v.Type())
+		key, val := reflect.New(v.Type().Key()).Elem(), reflect.New(v.Type().Elem()).Elem()
+		fill(key, n, depth+1)
+		fill(val, n, depth+1)
+		m.SetMapIndex(key, val)
+		v.Set(m)
+	case reflect.Pointer:
+		p := reflect.New(v.Type().Elem())
+		fill(p.Elem(), n, depth+1)
+		v.Set(p)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				fill(v.Field(i), n, depth+1)
+			}
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package conformance
+
+import (
+	"bytes"
+	"io"
+	"sort"
+	"testing"
+
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/sirupsen/logrus"
+)
+
+// Fuzz fuzzes the conversion of every packet that may be read from a connection using the protocol passed, both
+// by a listener and by a dialer. The convert function passed converts a decoded packet to the latest version
+// and must not recover from panics, so that they are reported. The corpus is seeded with every packet in the
+// pools of the protocol, filled the same way as by Test.
+func Fuzz(f *testing.F, proto minecraft.Protocol, convert func(pk packet.Packet, conn *minecraft.Conn) []packet.Packet) {
+	out := logrus.StandardLogger().Out
+	logrus.SetOutput(io.Discard)
+	f.Cleanup(func() {
+		logrus.SetOutput(out)
+	})
+
+	pools := map[bool]packet.Pool{true: proto.Packets(true), false: proto.Packets(false)}
+	for _, fromClient := range []bool{true, false} {
+		ids := make([]uint32, 0, len(pools[fromClient]))
+		for id := range pools[fromClient] {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		for _, id := range ids {
+			f.Add(fromClient, id, seed(proto, fromClient, id))
+		}
+	}
+
+	f.Fuzz(func(t *testing.T, fromClient bool, id uint32, payload []byte) {
+		pkFunc, ok := pools[fromClient][id]
+		if !ok {
+			return
+		}
+		pk := pkFunc()
+		if !decode(t, proto, pk, payload) {
+			return
+		}
+		convert(pk, &minecraft.Conn{})
+	})
+}
+
+// decode decodes the payload passed into pk. Like a minecraft.Conn, it recovers from the errors that packets
+// panic with while decoding, in which case false is returned and the packet is never converted.
+func decode(t *testing.T, proto minecraft.Protocol, pk packet.Packet, payload []byte) (ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, isErr := r.(error); !isErr {
+				t.Fatalf("%T: decoding panicked with a value that is not an error: %v", pk, r)
+			}
+			ok = false
+		}
+	}()
+	pk.Marshal(proto.NewReader(bytes.NewBuffer(payload), 0, true))
+	return true
+}
+
+// seed returns the payload of the packet with the ID passed, as it is encoded by the protocol. The packet is
+// filled and sanitised like in Test and then converted from the latest version. If the packet can't be
+// converted or encoded, an empty payload is returned.
+func seed(proto minecraft.Protocol, fromClient bool, id uint32) (payload []byte) {
+	latestPool := packet.NewServerPool()
+	if fromClient {
+		latestPool = packet.NewClientPool()
+	}
+	pkFunc, ok := latestPool[id]
+	if !ok {
+		return nil
+	}
+	defer func() {
+		if recover() != nil {
+			payload = nil
+		}
+	}()
+	pk := pkFunc()
+	Fill(pk)
+	if variants, ok := sanitisers[id]; ok {
+		variants[0](pk)
+	}
+	buf := bytes.NewBuffer(nil)
+	for _, legacy := range proto.ConvertFromLatest(pk, &minecraft.Conn{}) {
+		if legacy.ID() == id {
+			legacy.Marshal(proto.NewWriter(buf, 0))
+			return buf.Bytes()
+		}
+	}
+	return nil
+}
@@ -2,11 +2,16 @@ package latest
 
 import (
 	"bytes"
+	"cmp"
 	_ "embed"
+	"maps"
+	"slices"
+	"sync"
 
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/df-mc/worldupgrader/blockupgrader"
 	"github.com/sandertv/gophertunnel/minecraft/nbt"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
 )
 
 var (
@@ -24,10 +29,13 @@ var (
 	itemRuntimeIDsToNames = make(map[int32]string)
 	// itemNamesToRuntimeIDs holds a map to translate item string IDs to runtime IDs.
 	itemNamesToRuntimeIDs = make(map[string]int32)
+	// itemEntries holds the entries of all items, ordered by their runtime ID.
+	itemEntries []protocol.ItemEntry
 )
 
-// init initializes the item and block state mappings.
-func init() {
+// load initializes the item and block state mappings. It is called on first use of any of the mappings, so that
+// they are only decoded if needed.
+var load = sync.OnceFunc(func() {
 	dec := nbt.NewDecoder(bytes.NewBuffer(BlockStateData))
 
 	// Register all block states present in the block_states.nbt file. These are all possible options registered
@@ -53,36 +61,67 @@ func init() {
 	for name, rid := range m {
 		itemNamesToRuntimeIDs[name] = rid
 		itemRuntimeIDsToNames[rid] = name
+		itemEntries = append(itemEntries, protocol.ItemEntry{Name: name, RuntimeID: int16(rid)})
 	}
 	for _, it := range world.CustomItems() {
 		name, _ := it.EncodeItem()
 		rid, _, _ := world.ItemRuntimeID(it)
 		itemNamesToRuntimeIDs[name] = rid
 		itemRuntimeIDsToNames[rid] = name
+		itemEntries = append(itemEntries, protocol.ItemEntry{Name: name, RuntimeID: int16(rid), ComponentBased: true})
 	}
-}
+	slices.SortFunc(itemEntries, func(a, b protocol.ItemEntry) int {
+		return cmp.Compare(a.RuntimeID, b.RuntimeID)
+	})
+})
 
 // StateToRuntimeID converts a name and its state properties to a runtime ID.
 func StateToRuntimeID(name string, properties map[string]any) (runtimeID uint32, found bool) {
-	upgraded := blockupgrader.Upgrade(blockupgrader.BlockState{Name: name, Properties: properties})
+	load()
+	// The block upgrader modifies the properties passed to it, which may be those held by a mapping.
+	upgraded := blockupgrader.Upgrade(blockupgrader.BlockState{Name: name, Properties: maps.Clone(properties)})
 	rid, ok := stateToRuntimeID[HashState(upgraded)]
 	return rid, ok
 }
 
 // RuntimeIDToState converts a runtime ID to a name and its state properties.
 func RuntimeIDToState(runtimeID uint32) (name string, properties map[string]any, found bool) {
+	load()
 	s := runtimeIDToState[runtimeID]
 	return s.Name, s.Properties, true
 }
 
 // ItemRuntimeIDToName converts an item runtime ID to a string ID.
 func ItemRuntimeIDToName(runtimeID int32) (name string, found bool) {
+	load()
 	name, ok := itemRuntimeIDsToNames[runtimeID]
 	return name, ok
 }
 
 // ItemNameToRuntimeID converts a string ID to an item runtime ID.
 func ItemNameToRuntimeID(name string) (runtimeID int32, found bool) {
+	load()
 	rid, ok := itemNamesToRuntimeIDs[name]
 	return rid, ok
 }
+
+// BlockCount returns the amount of block states in the latest version. Block runtime IDs range from 0 up to,
+// but not including, BlockCount.
+func BlockCount() uint32 {
+	load()
+	return uint32(len(runtimeIDToState))
+}
+
+// ItemEntries returns the entries of all items in the latest version, ordered by their runtime ID. The slice
+// returned must not be modified.
+func ItemEntries() []protocol.ItemEntry {
+	load()
+	return itemEntries
+}
+
+// ItemRuntimeIDs returns a map of all item runtime IDs in the latest version and their string IDs. The map
+// returned must not be modified.
+func ItemRuntimeIDs() map[int32]string {
+	load()
+	return itemRuntimeIDsToNames
+}
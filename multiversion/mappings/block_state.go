@@ -3,6 +3,7 @@ package mappings
 import (
 	"bytes"
 	_ "embed"
+	"maps"
 
 	"github.com/df-mc/worldupgrader/blockupgrader"
 	"github.com/oomph-ac/mv/multiversion/latest"
@@ -21,6 +22,14 @@ type MVBlockMapping struct {
 	// LegacyAirRID is the runtime ID of the air block of that mapping.
 	LegacyAirRID uint32
 
+	// legacyStateToRuntimeID maps the hash of a block state, as found in the block palette before upgrading it, to
+	// a runtime ID.
+	legacyStateToRuntimeID map[latest.StateHash]uint32
+	// legacyStates holds the block states found in the block palette before upgrading them, indexed by name.
+	legacyStates map[string][]legacyState
+	// version is the version of the block states in the block palette.
+	version int32
+
 	// oldFormat is true if the block state data is in the old format.
 	oldFormat bool
 }
@@ -35,14 +44,21 @@ func blockMapping(blockStateData []byte, oldFormat bool) MVBlockMapping {
 	var blocks []protocol.BlockEntry
 	var stateToRuntimeID = make(map[latest.StateHash]uint32)
 	var runtimeIDToState = make(map[uint32]blockupgrader.BlockState)
+	var legacyStateToRuntimeID = make(map[latest.StateHash]uint32)
+	var legacyStates = make(map[string][]legacyState)
+	var version int32
 
 	for {
 		if err := dec.Decode(&s); err != nil {
 			break
 		}
 
-		s = blockupgrader.Upgrade(s)
 		rid := uint32(len(blocks))
+		legacyStateToRuntimeID[latest.HashState(s)] = rid
+		legacyStates[s.Name] = append(legacyStates[s.Name], legacyState{runtimeID: rid, properties: maps.Clone(s.Properties)})
+		version = max(version, s.Version)
+
+		s = blockupgrader.Upgrade(s)
 		blocks = append(blocks, protocol.BlockEntry{
 			Name:       s.Name,
 			Properties: s.Properties,
@@ -57,6 +73,10 @@ func blockMapping(blockStateData []byte, oldFormat bool) MVBlockMapping {
 		stateToRuntimeID: stateToRuntimeID,
 		runtimeIDToState: runtimeIDToState,
 
+		legacyStateToRuntimeID: legacyStateToRuntimeID,
+		legacyStates:           legacyStates,
+		version:                version,
+
 		oldFormat: oldFormat,
 	}
 	mappings.LegacyAirRID = mappings.StateToRuntimeID("minecraft:air", nil)
@@ -64,14 +84,64 @@ func blockMapping(blockStateData []byte, oldFormat bool) MVBlockMapping {
 	return mappings
 }
 
-// StateToRuntimeID converts a name and its state properties to a runtime ID.
+// legacyState is a block state as found in a block palette, before upgrading it.
+type legacyState struct {
+	runtimeID  uint32
+	properties map[string]any
+}
+
+// StateToRuntimeID converts a name and its state properties of the latest version to a runtime ID. States that
+// were renamed or had their properties changed since the version of the mapping are converted back to the state
+// they had in that version. If the block does not exist in that version, the runtime ID of minecraft:info_update
+// is returned.
 func (m MVBlockMapping) StateToRuntimeID(name string, properties map[string]any) uint32 {
-	rid, ok := m.stateToRuntimeID[latest.HashState(blockupgrader.BlockState{Name: name, Properties: properties})]
-	if !ok {
-		rid = m.stateToRuntimeID[latest.HashState(blockupgrader.BlockState{Name: "minecraft:info_update"})]
+	rid, _ := m.downgradeState(name, properties)
+	return rid
+}
+
+// downgradeState converts a name and its state properties of the latest version to a runtime ID. The bool
+// returned is true if the state has an exact counterpart in the version of the mapping. If not, the closest
+// state of the same block is returned, or minecraft:info_update if the block does not exist in that version.
+func (m MVBlockMapping) downgradeState(name string, properties map[string]any) (uint32, bool) {
+	if rid, ok := m.stateToRuntimeID[latest.HashState(blockupgrader.BlockState{Name: name, Properties: properties})]; ok {
+		return rid, true
 	}
+	candidates := downgradeState(blockupgrader.BlockState{Name: name, Properties: properties}, m.version)
+	for _, c := range candidates {
+		if rid, ok := m.legacyStateToRuntimeID[latest.HashState(c)]; ok {
+			return rid, true
+		}
+	}
+	for _, c := range candidates {
+		if rid, ok := m.closestState(c); ok {
+			return rid, false
+		}
+	}
+	return m.stateToRuntimeID[latest.HashState(blockupgrader.BlockState{Name: "minecraft:info_update"})], false
+}
 
-	return rid
+// closestState returns the runtime ID of the legacy state with the same name as the state passed that has the
+// most property values in common with it. States with a property of a different value are not considered, so
+// that only properties of which the value is unknown may differ. False is returned if no such state exists.
+func (m MVBlockMapping) closestState(state blockupgrader.BlockState) (uint32, bool) {
+	var rid uint32
+	best := -1
+	for _, s := range m.legacyStates[state.Name] {
+		score := 0
+		for k, v := range s.properties {
+			if want, ok := state.Properties[k]; ok {
+				if want != v {
+					score = -1
+					break
+				}
+				score++
+			}
+		}
+		if score > best {
+			rid, best = s.runtimeID, score
+		}
+	}
+	return rid, best >= 0
 }
 
 // RuntimeIDToState converts a runtime ID to a name and its state properties.
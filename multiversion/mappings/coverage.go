@@ -0,0 +1,72 @@
+package mappings
+
+import (
+	"sort"
+
+	"github.com/oomph-ac/mv/multiversion/latest"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// CoverageReport lists the block states and items of the latest version that have no exact counterpart in an
+// MVMapping, along with what each of them is translated to instead.
+type CoverageReport struct {
+	// Blocks holds the block states without an exact counterpart, ordered by their runtime ID.
+	Blocks []BlockFallback
+	// Items holds the items without an exact counterpart, ordered by their runtime ID.
+	Items []ItemFallback
+}
+
+// BlockFallback is a block state of the latest version that has no exact counterpart in an MVMapping.
+type BlockFallback struct {
+	// RuntimeID is the runtime ID of the block state in the latest version.
+	RuntimeID uint32
+	// State is the block state in the latest version.
+	State protocol.BlockEntry
+	// FallbackRuntimeID is the legacy runtime ID that the block state is downgraded to, and Fallback the legacy
+	// block state with that runtime ID, usually minecraft:info_update.
+	FallbackRuntimeID uint32
+	Fallback          protocol.BlockEntry
+}
+
+// ItemFallback is an item of the latest version that has no item with the same name in an MVMapping.
+type ItemFallback struct {
+	// RuntimeID is the runtime ID of the item in the latest version.
+	RuntimeID int32
+	// Name is the name of the item in the latest version.
+	Name string
+	// Fallback is the name of the legacy item that the item shows up as, which is always minecraft:name_tag.
+	Fallback string
+}
+
+// Coverage returns a CoverageReport of the MVMapping passed, listing every block state and item of the latest
+// version that the mapping does not hold an exact counterpart for.
+func Coverage(m MVMapping) CoverageReport {
+	var report CoverageReport
+	for rid := uint32(0); rid < latest.BlockCount(); rid++ {
+		name, properties, _ := latest.RuntimeIDToState(rid)
+		fallbackRID, exact := m.downgradeState(name, properties)
+		if exact {
+			continue
+		}
+		fallbackName, fallbackProperties, _ := m.RuntimeIDToState(fallbackRID)
+		report.Blocks = append(report.Blocks, BlockFallback{
+			RuntimeID:         rid,
+			State:             protocol.BlockEntry{Name: name, Properties: properties},
+			FallbackRuntimeID: fallbackRID,
+			Fallback:          protocol.BlockEntry{Name: fallbackName, Properties: fallbackProperties},
+		})
+	}
+
+	for rid, name := range latest.ItemRuntimeIDs() {
+		if _, ok := m.itemNamesToRuntimeIDs[name]; ok {
+			continue
+		}
+		fallbackID, _ := m.ItemIDByName(name)
+		fallback, _ := m.ItemNameByID(fallbackID)
+		report.Items = append(report.Items, ItemFallback{RuntimeID: rid, Name: name, Fallback: fallback})
+	}
+	sort.Slice(report.Items, func(i, j int) bool {
+		return report.Items[i].RuntimeID < report.Items[j].RuntimeID
+	})
+	return report
+}
@@ -0,0 +1,53 @@
+package mappings_test
+
+import (
+	"testing"
+
+	"github.com/oomph-ac/mv/multiversion/latest"
+	"github.com/oomph-ac/mv/multiversion/mappings"
+	"github.com/oomph-ac/mv/multiversion/mv582"
+)
+
+// TestCoverage tests that the coverage report of a mapping lists the blocks and items missing in its version
+// along with their fallbacks, and nothing else.
+func TestCoverage(t *testing.T) {
+	m := mv582.Mapping()
+	report := mappings.Coverage(m)
+
+	crafter := false
+	for _, b := range report.Blocks {
+		if b.State.Name == "minecraft:stone" {
+			t.Errorf("unexpected block %v in report", b.State.Name)
+		}
+		if b.FallbackRuntimeID != m.DowngradeBlockRuntimeID(b.RuntimeID) {
+			t.Errorf("block %v: fallback %v does not match downgrade", b.State.Name, b.FallbackRuntimeID)
+		}
+		if b.State.Name == "minecraft:crafter" {
+			crafter = true
+			if b.Fallback.Name != "minecraft:info_update" {
+				t.Errorf("expected crafter to fall back to minecraft:info_update, got %v", b.Fallback.Name)
+			}
+		}
+	}
+	if !crafter {
+		t.Errorf("expected minecraft:crafter in report")
+	}
+
+	found := false
+	for _, it := range report.Items {
+		if _, ok := m.ItemIDByName(it.Name); ok {
+			t.Errorf("unexpected item %v in report", it.Name)
+		}
+		if it.Fallback != "minecraft:name_tag" {
+			t.Errorf("expected item %v to fall back to minecraft:name_tag, got %v", it.Name, it.Fallback)
+		}
+		found = found || it.Name == "minecraft:crafter"
+	}
+	if !found {
+		t.Errorf("expected item minecraft:crafter in report")
+	}
+
+	if report := mappings.Coverage(mappings.Mapping(latest.BlockStateData, latest.ItemRuntimeIDData, false)); len(report.Blocks) != 0 || len(report.Items) != 0 {
+		t.Errorf("expected the latest mapping to be fully covered, got %v blocks and %v items", len(report.Blocks), len(report.Items))
+	}
+}
@@ -1,15 +1,61 @@
 package mappings
 
+import (
+	"sync"
+
+	"github.com/cespare/xxhash/v2"
+)
+
 // MVMapping holds all data blocks, items related.
 type MVMapping struct {
 	MVBlockMapping
 	MVItemMapping
+
+	// tables holds the dense tables used to translate runtime IDs from and to the latest version.
+	tables *translationTables
+}
+
+var (
+	// mu guards blockMappings, itemMappings and tables.
+	mu sync.Mutex
+	// blockMappings and itemMappings hold the block and item mappings decoded so far, indexed by a hash of the
+	// data they were decoded from.
+	blockMappings = map[dataKey]MVBlockMapping{}
+	itemMappings  = map[dataKey]MVItemMapping{}
+	// tables holds the translation tables computed so far, indexed by the data of the block and item mappings
+	// they were computed for.
+	tables = map[[2]dataKey]*translationTables{}
+)
+
+// dataKey identifies the data a block or item mapping was decoded from.
+type dataKey struct {
+	hash      uint64
+	oldFormat bool
 }
 
 // Mapping returns MVMapping instance of all block and item entries and values in the maps from the resource JSON.
+// Protocols that use the same block palette or item table share the data decoded from it, so that it is held in
+// memory only once.
 func Mapping(blockStateData, itemRuntimeIDData []byte, oldFormat bool) MVMapping {
-	return MVMapping{
-		MVBlockMapping: blockMapping(blockStateData, oldFormat),
-		MVItemMapping:  itemMapping(itemRuntimeIDData),
+	blockKey := dataKey{hash: xxhash.Sum64(blockStateData), oldFormat: oldFormat}
+	itemKey := dataKey{hash: xxhash.Sum64(itemRuntimeIDData)}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	var m MVMapping
+	var ok bool
+	if m.MVBlockMapping, ok = blockMappings[blockKey]; !ok {
+		m.MVBlockMapping = blockMapping(blockStateData, oldFormat)
+		blockMappings[blockKey] = m.MVBlockMapping
+	}
+	if m.MVItemMapping, ok = itemMappings[itemKey]; !ok {
+		m.MVItemMapping = itemMapping(itemRuntimeIDData)
+		itemMappings[itemKey] = m.MVItemMapping
+	}
+	if m.tables, ok = tables[[2]dataKey{blockKey, itemKey}]; !ok {
+		m.tables = newTranslationTables(m)
+		tables[[2]dataKey{blockKey, itemKey}] = m.tables
 	}
+	return m
 }
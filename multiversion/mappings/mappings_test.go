@@ -0,0 +1,25 @@
+package mappings_test
+
+import (
+	"testing"
+
+	"github.com/oomph-ac/mv/multiversion/mappings"
+	"github.com/oomph-ac/mv/multiversion/mv618"
+	"github.com/oomph-ac/mv/multiversion/mv622"
+	"github.com/oomph-ac/mv/multiversion/mv630"
+	"github.com/oomph-ac/mv/multiversion/mv649"
+)
+
+// TestMappingShared tests that protocols with the same item table share the data decoded from it, while their
+// block palettes, which differ, are decoded separately.
+func TestMappingShared(t *testing.T) {
+	for _, pair := range [][2]func() mappings.MVMapping{{mv618.Mapping, mv622.Mapping}, {mv630.Mapping, mv649.Mapping}} {
+		a, b := pair[0](), pair[1]()
+		if &a.Items()[0] != &b.Items()[0] {
+			t.Errorf("expected item mappings to be shared")
+		}
+		if &a.Blocks()[0] == &b.Blocks()[0] {
+			t.Errorf("expected block mappings not to be shared")
+		}
+	}
+}
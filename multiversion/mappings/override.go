@@ -0,0 +1,313 @@
+package mappings
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/df-mc/worldupgrader/blockupgrader"
+	"github.com/oomph-ac/mv/multiversion/latest"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+)
+
+const (
+	// BlockStatesFile is the name of the file holding the block palette of a protocol in an overrides fs.FS.
+	BlockStatesFile = "block_states.nbt"
+	// ItemRuntimeIDsFile is the name of the file holding the item table of a protocol in an overrides fs.FS.
+	ItemRuntimeIDsFile = "item_runtime_ids.nbt"
+)
+
+var (
+	// overrideMu guards overrides, loaded and registered.
+	overrideMu sync.Mutex
+	// overrides holds the overrides set using SetOverrides, indexed by protocol ID.
+	overrides = map[int32]override{}
+	// loaded holds the protocol IDs of all mappings loaded using Load.
+	loaded = map[int32]struct{}{}
+	// registered holds the block palettes registered using Register, indexed by protocol ID.
+	registered = map[int32][]byte{}
+)
+
+// override holds the data that overrides the embedded mappings of a protocol.
+type override struct {
+	// blockStates replaces the embedded block palette if non-nil. It holds every block state of the latest
+	// palette that the embedded palette holds.
+	blockStates []byte
+	// items is layered on top of the embedded item table.
+	items map[string]int32
+}
+
+// ValidationError is returned by SetOverrides if any of the overrides are invalid. It holds every problem found.
+type ValidationError struct {
+	Problems []string
+}
+
+// Error ...
+func (err *ValidationError) Error() string {
+	return fmt.Sprintf("invalid mapping overrides:\n\t%v", strings.Join(err.Problems, "\n\t"))
+}
+
+// Register registers the block palette embedded in the package of the protocol with the ID passed. Overrides may
+// only be set for protocols registered, and block palettes overriding the embedded palette are checked against
+// it. Protocols register their palette when their package is initialised.
+func Register(protocol int32, blockStateData []byte) {
+	overrideMu.Lock()
+	defer overrideMu.Unlock()
+	registered[protocol] = blockStateData
+}
+
+// SetOverrides sets the mappings that override the embedded mappings of protocols. The fs.FS passed holds a
+// directory for each protocol overridden, named after its protocol ID, for example "589". A directory may hold
+// a block_states.nbt file, which replaces the block palette of the protocol, and an item_runtime_ids.nbt file,
+// whose entries are layered on top of the item table of the protocol: they are added to the table, replacing
+// entries with the same name or runtime ID. As the runtime IDs of blocks follow from their position in the
+// palette, block palettes are not layered: An overriding palette must be complete and hold every block state
+// of the latest version that the embedded palette holds.
+//
+// All overrides are validated before any of them are set. If any problems are found, such as files that can't
+// be decoded, duplicate block states or runtime IDs, palettes missing air, minecraft:info_update or states of
+// the embedded palette and directories of protocols that are not registered, a *ValidationError is returned.
+// Overrides must be set before the mappings of the protocols are loaded, so SetOverrides should be called
+// before accepting any connections.
+func SetOverrides(fsys fs.FS) error {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return fmt.Errorf("read overrides: %w", err)
+	}
+	var problems []string
+	set := map[int32]override{}
+	for _, entry := range entries {
+		id, err := strconv.ParseInt(entry.Name(), 10, 32)
+		if !entry.IsDir() || err != nil {
+			problems = append(problems, fmt.Sprintf("%v: expected a directory named after a protocol ID", entry.Name()))
+			continue
+		}
+		o, p, err := readOverride(fsys, entry.Name())
+		if err != nil {
+			return err
+		}
+		problems = append(problems, p...)
+		set[int32(id)] = o
+	}
+
+	overrideMu.Lock()
+	defer overrideMu.Unlock()
+	for id, o := range set {
+		embedded, ok := registered[id]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%v: no protocol with the ID is registered", id))
+			continue
+		}
+		if _, ok := loaded[id]; ok {
+			problems = append(problems, fmt.Sprintf("%v: mappings of the protocol were already loaded", id))
+		}
+		if o.blockStates != nil {
+			for _, p := range missingBlockStates(embedded, o.blockStates) {
+				problems = append(problems, fmt.Sprintf("%v: %v", path.Join(strconv.Itoa(int(id)), BlockStatesFile), p))
+			}
+		}
+	}
+	sort.Strings(problems)
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	for id, o := range set {
+		overrides[id] = o
+	}
+	return nil
+}
+
+// readOverride reads the override in the directory passed. It returns the override, any problems found in it
+// and an error if the directory could not be read.
+func readOverride(fsys fs.FS, dir string) (o override, problems []string, err error) {
+	files, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return o, nil, fmt.Errorf("read overrides: %w", err)
+	}
+	for _, f := range files {
+		name := path.Join(dir, f.Name())
+		if f.Name() != BlockStatesFile && f.Name() != ItemRuntimeIDsFile {
+			problems = append(problems, fmt.Sprintf("%v: unknown file", name))
+			continue
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return o, nil, fmt.Errorf("read overrides: %w", err)
+		}
+		if f.Name() == BlockStatesFile {
+			o.blockStates = data
+			for _, p := range validateBlockStates(data) {
+				problems = append(problems, fmt.Sprintf("%v: %v", name, p))
+			}
+			continue
+		}
+		var p []string
+		o.items, p = validateItems(data)
+		for _, p := range p {
+			problems = append(problems, fmt.Sprintf("%v: %v", name, p))
+		}
+	}
+	return o, problems, nil
+}
+
+// Validate validates a complete block palette and item table, such as those embedded in the package of a protocol.
+// A nil block palette or item table is not validated. If any problems are found, a *ValidationError is returned.
+func Validate(blockStateData, itemRuntimeIDData []byte) error {
+	var problems []string
+	if blockStateData != nil {
+		for _, p := range validateBlockStates(blockStateData) {
+			problems = append(problems, fmt.Sprintf("%v: %v", BlockStatesFile, p))
+		}
+	}
+	if itemRuntimeIDData != nil {
+		items, p := validateItems(itemRuntimeIDData)
+		if _, ok := items["minecraft:name_tag"]; !ok && items != nil {
+			p = append(p, "missing item minecraft:name_tag")
+		}
+		for _, p := range p {
+			problems = append(problems, fmt.Sprintf("%v: %v", ItemRuntimeIDsFile, p))
+		}
+	}
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// validateBlockStates returns the problems found in the block palette passed.
+func validateBlockStates(data []byte) (problems []string) {
+	buf := bytes.NewBuffer(data)
+	dec := nbt.NewDecoder(buf)
+	seen := map[latest.StateHash]int{}
+	for i := 0; buf.Len() > 0; i++ {
+		var s blockupgrader.BlockState
+		if err := dec.Decode(&s); err != nil {
+			problems = append(problems, fmt.Sprintf("decode block state %v: %v", i, err))
+			break
+		}
+		// States are compared as found in the palette: older palettes hold states that are distinct in their
+		// version but upgrade to the same state.
+		h := latest.HashState(s)
+		if j, ok := seen[h]; ok {
+			problems = append(problems, fmt.Sprintf("duplicate block state %v %v: runtime IDs %v and %v", s.Name, s.Properties, j, i))
+			continue
+		}
+		seen[h] = i
+	}
+	for _, name := range []string{"minecraft:air", "minecraft:info_update"} {
+		if _, ok := seen[latest.HashState(blockupgrader.BlockState{Name: name})]; !ok {
+			problems = append(problems, fmt.Sprintf("missing block state %v", name))
+		}
+	}
+	return problems
+}
+
+// missingBlockStates returns a problem for the block states of the latest palette that the embedded palette
+// passed holds, but the overriding palette passed does not.
+func missingBlockStates(embedded, override []byte) (problems []string) {
+	have := latestRuntimeIDs(override)
+	var missing []uint32
+	for rid := range latestRuntimeIDs(embedded) {
+		if _, ok := have[rid]; !ok {
+			missing = append(missing, rid)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	sort.Slice(missing, func(i, j int) bool { return missing[i] < missing[j] })
+	names := make([]string, 0, 3)
+	for _, rid := range missing[:min(len(missing), cap(names))] {
+		name, properties, _ := latest.RuntimeIDToState(rid)
+		names = append(names, fmt.Sprintf("%v %v", name, properties))
+	}
+	return []string{fmt.Sprintf("missing %v block states of the embedded palette, such as %v: block palettes must be complete", len(missing), strings.Join(names, ", "))}
+}
+
+// latestRuntimeIDs returns the runtime IDs in the latest version of the block states in the palette passed. The
+// palette is decoded up to the first block state that can't be decoded.
+func latestRuntimeIDs(data []byte) map[uint32]struct{} {
+	buf := bytes.NewBuffer(data)
+	dec := nbt.NewDecoder(buf)
+	rids := map[uint32]struct{}{}
+	for buf.Len() > 0 {
+		var s blockupgrader.BlockState
+		if err := dec.Decode(&s); err != nil {
+			break
+		}
+		if rid, ok := latest.StateToRuntimeID(s.Name, s.Properties); ok {
+			rids[rid] = struct{}{}
+		}
+	}
+	return rids
+}
+
+// validateItems decodes the item table passed and returns it along with the problems found in it.
+func validateItems(data []byte) (items map[string]int32, problems []string) {
+	if err := nbt.Unmarshal(data, &items); err != nil {
+		return nil, []string{fmt.Sprintf("decode item table: %v", err)}
+	}
+	names := map[int32][]string{}
+	for name, id := range items {
+		names[id] = append(names[id], name)
+	}
+	for id, n := range names {
+		if len(n) > 1 {
+			sort.Strings(n)
+			problems = append(problems, fmt.Sprintf("duplicate item runtime ID %v: %v", id, strings.Join(n, ", ")))
+		}
+	}
+	sort.Strings(problems)
+	return items, problems
+}
+
+// Load returns the mappings of the protocol with the ID passed. The block palette and item table passed are the
+// defaults embedded in the package of the protocol. A block palette set using SetOverrides replaces the palette
+// passed, while an item table set using it is layered on top of the table passed.
+func Load(protocol int32, blockStateData, itemRuntimeIDData []byte, oldFormat bool) MVMapping {
+	overrideMu.Lock()
+	o, ok := overrides[protocol]
+	loaded[protocol] = struct{}{}
+	overrideMu.Unlock()
+
+	if ok {
+		if o.blockStates != nil {
+			blockStateData = o.blockStates
+		}
+		if o.items != nil {
+			itemRuntimeIDData = layerItems(itemRuntimeIDData, o.items)
+		}
+	}
+	return Mapping(blockStateData, itemRuntimeIDData, oldFormat)
+}
+
+// layerItems layers the items passed on top of the encoded item table and returns the encoded result. Entries of
+// the table with the same name or runtime ID as any of the items are replaced.
+func layerItems(itemRuntimeIDData []byte, items map[string]int32) []byte {
+	var m map[string]int32
+	if err := nbt.Unmarshal(itemRuntimeIDData, &m); err != nil {
+		panic(err)
+	}
+	ids := make(map[int32]struct{}, len(items))
+	for _, id := range items {
+		ids[id] = struct{}{}
+	}
+	for name, id := range m {
+		if _, ok := ids[id]; ok {
+			delete(m, name)
+		}
+	}
+	for name, id := range items {
+		m[name] = id
+	}
+	data, err := nbt.Marshal(m)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
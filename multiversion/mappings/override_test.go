@@ -0,0 +1,131 @@
+package mappings
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/df-mc/worldupgrader/blockupgrader"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+)
+
+// TestSetOverrides tests that overriding block palettes replace the default palette passed to Load, and that
+// overriding item tables are layered on top of the default item table.
+func TestSetOverrides(t *testing.T) {
+	defaults := encodeStates(t, "minecraft:air", "minecraft:info_update", "minecraft:stone")
+	items := encodeItems(t, map[string]int32{"minecraft:name_tag": 1, "minecraft:stick": 2, "minecraft:apple": 3})
+	Register(1, defaults)
+	Register(2, defaults)
+
+	err := SetOverrides(fstest.MapFS{
+		"1/" + BlockStatesFile:    {Data: encodeStates(t, "minecraft:info_update", "minecraft:stone", "minecraft:air")},
+		"1/" + ItemRuntimeIDsFile: {Data: encodeItems(t, map[string]int32{"minecraft:stick": 4, "minecraft:bread": 3})},
+	})
+	if err != nil {
+		t.Fatalf("set overrides: %v", err)
+	}
+	m := Load(1, defaults, items, false)
+	if m.LegacyAirRID != 2 {
+		t.Errorf("expected air to have runtime ID 2 in the overridden palette, got %v", m.LegacyAirRID)
+	}
+	want := map[string]int32{"minecraft:name_tag": 1, "minecraft:stick": 4, "minecraft:bread": 3}
+	for name, id := range want {
+		if got, ok := m.ItemIDByName(name); !ok || got != id {
+			t.Errorf("%v: expected runtime ID %v, got %v (%v)", name, id, got, ok)
+		}
+	}
+	if _, ok := m.ItemNameByID(2); ok {
+		t.Errorf("expected runtime ID 2 to be removed")
+	}
+	if name, _ := m.ItemNameByID(3); name != "minecraft:bread" {
+		t.Errorf("expected runtime ID 3 to be replaced by minecraft:bread, got %v", name)
+	}
+
+	if m := Load(2, defaults, items, false); m.LegacyAirRID != 0 {
+		t.Errorf("expected protocols without overrides to use the defaults")
+	}
+	if err := SetOverrides(fstest.MapFS{"2/" + BlockStatesFile: {Data: defaults}}); err == nil {
+		t.Errorf("expected an error setting overrides of a loaded protocol")
+	}
+}
+
+// TestSetOverridesValidation tests that SetOverrides reports every problem in the overrides passed.
+func TestSetOverridesValidation(t *testing.T) {
+	defaults := encodeStates(t, "minecraft:air", "minecraft:info_update", "minecraft:stone", "minecraft:glass")
+	Register(3, defaults)
+	Register(4, defaults)
+	Register(5, defaults)
+
+	err := SetOverrides(fstest.MapFS{
+		"3/" + BlockStatesFile:    {Data: encodeStates(t, "minecraft:stone", "minecraft:stone")},
+		"3/" + ItemRuntimeIDsFile: {Data: encodeItems(t, map[string]int32{"minecraft:stick": 1, "minecraft:apple": 1})},
+		"3/blocks.json":           {Data: []byte("{}")},
+		"4/" + BlockStatesFile:    {Data: []byte{0xff}},
+		"5/" + BlockStatesFile:    {Data: encodeStates(t, "minecraft:air", "minecraft:info_update", "minecraft:stone")},
+		"6/" + ItemRuntimeIDsFile: {Data: encodeItems(t, map[string]int32{"minecraft:stick": 1})},
+		"palette.nbt":             {Data: []byte{}},
+	})
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	for _, want := range []string{
+		"3/block_states.nbt: duplicate block state minecraft:stone",
+		"3/block_states.nbt: missing block state minecraft:air",
+		"3/block_states.nbt: missing block state minecraft:info_update",
+		"3/item_runtime_ids.nbt: duplicate item runtime ID 1: minecraft:apple, minecraft:stick",
+		"3/blocks.json: unknown file",
+		"4/block_states.nbt: decode block state 0",
+		"5/block_states.nbt: missing 1 block states of the embedded palette, such as minecraft:glass map[]",
+		"6: no protocol with the ID is registered",
+		"palette.nbt: expected a directory",
+	} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected problem %q, got:\n%v", want, err)
+		}
+	}
+
+	overrideMu.Lock()
+	_, ok := overrides[3]
+	overrideMu.Unlock()
+	if ok {
+		t.Errorf("expected invalid overrides not to be set")
+	}
+}
+
+// encodeStates encodes a block palette holding blocks without properties with the names passed.
+func encodeStates(t *testing.T, names ...string) []byte {
+	buf := bytes.NewBuffer(nil)
+	enc := nbt.NewEncoder(buf)
+	for _, name := range names {
+		s := blockupgrader.BlockState{Name: name, Properties: map[string]any{}, Version: math.MaxInt32}
+		if err := enc.Encode(s); err != nil {
+			t.Fatalf("encode block state: %v", err)
+		}
+	}
+	return buf.Bytes()
+}
+
+// encodeItems encodes the item table passed.
+func encodeItems(t *testing.T, items map[string]int32) []byte {
+	data, err := nbt.Marshal(items)
+	if err != nil {
+		t.Fatalf("encode item table: %v", err)
+	}
+	return data
+}
+
+// TestValidate tests that Validate reports problems in complete mappings, such as missing fallbacks.
+func TestValidate(t *testing.T) {
+	blocks := encodeStates(t, "minecraft:air", "minecraft:info_update")
+	if err := Validate(blocks, encodeItems(t, map[string]int32{"minecraft:name_tag": 1})); err != nil {
+		t.Errorf("expected valid mappings, got %v", err)
+	}
+	err := Validate(blocks, encodeItems(t, map[string]int32{"minecraft:stick": 1}))
+	if err == nil || !strings.Contains(err.Error(), "item_runtime_ids.nbt: missing item minecraft:name_tag") {
+		t.Errorf("expected a missing name tag to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,389 @@
+package mappings
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/df-mc/worldupgrader/blockupgrader"
+	"github.com/oomph-ac/mv/multiversion/latest"
+)
+
+var (
+	// schemaFS holds the block state upgrade schemas of worldupgrader that apply to the versions supported. They
+	// must be kept in sync with the version of worldupgrader used to upgrade block states.
+	//go:embed schemas/*.json
+	schemaFS embed.FS
+	// downgradeSchemas holds the inverse of every schema in schemaFS, ordered from the newest to the oldest.
+	downgradeSchemas = readDowngradeSchemas()
+)
+
+// downgradeSchema is the inverse of a block state upgrade schema. Where a schema upgrades a block state from an
+// older version to the version of the schema, a downgradeSchema converts a block state of that version back to
+// the state, or states, it may have been upgraded from.
+type downgradeSchema struct {
+	// id is the version of the schema. Block states with a version up to and including id are upgraded by it.
+	id int32
+
+	// oldNames maps a new block name to the names that were renamed to it.
+	oldNames map[string][]string
+	// addedProperties holds the properties added to a block, indexed by its old name.
+	addedProperties map[string][]string
+	// renamedProperties maps the new name of a property to its old name, indexed by the old name of the block.
+	renamedProperties map[string]map[string]string
+	// remappedPropertyValues holds the value remaps of a property, indexed by the old name of the block and the
+	// old name of the property.
+	remappedPropertyValues map[string]map[string][]valueRemap
+	// remappedStates holds all states that were remapped to a different block.
+	remappedStates []stateRemap
+}
+
+// valueRemap holds the old and new value of a remapped property.
+type valueRemap struct {
+	Old schemaTag `json:"old"`
+	New schemaTag `json:"new"`
+}
+
+// stateRemap holds a block state remapped to a different block, either with a fixed name or a name built from
+// the value of one of its properties.
+type stateRemap struct {
+	oldName string
+
+	OldState         map[string]schemaTag `json:"oldState"`
+	NewName          string               `json:"newName"`
+	NewFlattenedName *flattenedName       `json:"newFlattenedName"`
+	NewState         map[string]schemaTag `json:"newState"`
+	CopiedState      []string             `json:"copiedState"`
+}
+
+// flattenedName is the name of a block that is built from the value of one of the properties of its old state.
+type flattenedName struct {
+	Prefix               string            `json:"prefix"`
+	FlattenedProperty    string            `json:"flattenedProperty"`
+	Suffix               string            `json:"suffix"`
+	FlattenedValueRemaps map[string]string `json:"flattenedValueRemaps"`
+}
+
+// schemaTag is a property value in a schema.
+type schemaTag struct {
+	Byte   *byte   `json:"byte"`
+	Int    *int32  `json:"int"`
+	String *string `json:"string"`
+}
+
+// value returns the value held by the tag.
+func (t schemaTag) value() any {
+	switch {
+	case t.Byte != nil:
+		return *t.Byte
+	case t.Int != nil:
+		return *t.Int
+	case t.String != nil:
+		return *t.String
+	}
+	return nil
+}
+
+// schemaModel is the JSON format of a block state upgrade schema.
+type schemaModel struct {
+	MaxVersionMajor    int32 `json:"maxVersionMajor"`
+	MaxVersionMinor    int32 `json:"maxVersionMinor"`
+	MaxVersionPatch    int32 `json:"maxVersionPatch"`
+	MaxVersionRevision int32 `json:"maxVersionRevision"`
+
+	RenamedIDs                  map[string]string               `json:"renamedIds"`
+	AddedProperties             map[string]map[string]schemaTag `json:"addedProperties"`
+	RenamedProperties           map[string]map[string]string    `json:"renamedProperties"`
+	RemappedPropertyValues      map[string]map[string]string    `json:"remappedPropertyValues"`
+	RemappedPropertyValuesIndex map[string][]valueRemap         `json:"remappedPropertyValuesIndex"`
+	RemappedStates              map[string][]stateRemap         `json:"remappedStates"`
+}
+
+// readDowngradeSchemas reads the schemas embedded in schemaFS and returns their inverse, ordered from the newest
+// to the oldest.
+func readDowngradeSchemas() []downgradeSchema {
+	files, err := schemaFS.ReadDir("schemas")
+	if err != nil {
+		panic(err)
+	}
+	schemas := make([]downgradeSchema, 0, len(files))
+	// Schemas are applied in the order of their file names when upgrading, so they are inverted in reverse order.
+	for i := len(files) - 1; i >= 0; i-- {
+		f := files[i]
+		data, err := schemaFS.ReadFile("schemas/" + f.Name())
+		if err != nil {
+			panic(err)
+		}
+		var m schemaModel
+		if err := json.Unmarshal(data, &m); err != nil {
+			panic(fmt.Errorf("decode schema %v: %w", f.Name(), err))
+		}
+		s, err := newDowngradeSchema(m)
+		if err != nil {
+			panic(fmt.Errorf("decode schema %v: %w", f.Name(), err))
+		}
+		schemas = append(schemas, s)
+	}
+	sort.SliceStable(schemas, func(i, j int) bool {
+		return schemas[i].id > schemas[j].id
+	})
+	return schemas
+}
+
+// newDowngradeSchema creates the inverse of the schema passed.
+func newDowngradeSchema(m schemaModel) (downgradeSchema, error) {
+	s := downgradeSchema{
+		id:                     m.MaxVersionMajor<<24 | m.MaxVersionMinor<<16 | m.MaxVersionPatch<<8 | m.MaxVersionRevision,
+		oldNames:               map[string][]string{},
+		addedProperties:        map[string][]string{},
+		renamedProperties:      map[string]map[string]string{},
+		remappedPropertyValues: map[string]map[string][]valueRemap{},
+	}
+	for oldName, newName := range m.RenamedIDs {
+		s.oldNames[newName] = append(s.oldNames[newName], oldName)
+	}
+	for name, properties := range m.AddedProperties {
+		for k := range properties {
+			s.addedProperties[name] = append(s.addedProperties[name], k)
+		}
+	}
+	for name, properties := range m.RenamedProperties {
+		s.renamedProperties[name] = map[string]string{}
+		for oldKey, newKey := range properties {
+			s.renamedProperties[name][newKey] = oldKey
+		}
+	}
+	for name, properties := range m.RemappedPropertyValues {
+		s.remappedPropertyValues[name] = map[string][]valueRemap{}
+		for k, index := range properties {
+			remaps, ok := m.RemappedPropertyValuesIndex[index]
+			if !ok {
+				return s, fmt.Errorf("missing key from values index: %v", index)
+			}
+			s.remappedPropertyValues[name][k] = remaps
+		}
+	}
+	for oldName, remaps := range m.RemappedStates {
+		for _, remap := range remaps {
+			if remap.NewName == "" && remap.NewFlattenedName == nil {
+				return s, fmt.Errorf("remapped state of %v has no new name", oldName)
+			}
+			remap.oldName = oldName
+			s.remappedStates = append(s.remappedStates, remap)
+		}
+	}
+	// Map iteration order is random, so the remapped states are sorted to downgrade states the same way every time.
+	sort.SliceStable(s.remappedStates, func(i, j int) bool {
+		return s.remappedStates[i].oldName < s.remappedStates[j].oldName
+	})
+	for _, names := range s.oldNames {
+		sort.Strings(names)
+	}
+	return s, nil
+}
+
+// downgradeState converts a block state of the latest version to the states it may have had in a version using
+// block states of the version passed, by applying the inverse of every newer schema. Upgrading is not always
+// reversible, so multiple candidates may be returned, ordered by how likely they are. Properties of which the
+// old value can't be known, such as removed properties, are left out of the candidates.
+func downgradeState(state blockupgrader.BlockState, version int32) []blockupgrader.BlockState {
+	states := []blockupgrader.BlockState{state}
+	for _, s := range downgradeSchemas {
+		if s.id < version {
+			break
+		}
+		var downgraded []blockupgrader.BlockState
+		seen := map[latest.StateHash]struct{}{}
+		for _, state := range states {
+			for _, c := range s.downgrade(state) {
+				h := latest.HashState(c)
+				if _, ok := seen[h]; ok {
+					continue
+				}
+				seen[h] = struct{}{}
+				downgraded = append(downgraded, c)
+			}
+		}
+		states = downgraded
+	}
+	return states
+}
+
+// downgrade returns the states that the state passed may have been upgraded from by the schema.
+func (s downgradeSchema) downgrade(state blockupgrader.BlockState) []blockupgrader.BlockState {
+	names := s.oldNames[state.Name]
+	if !s.renamed(state.Name) {
+		// The block kept its name, which is the most likely.
+		names = append([]string{state.Name}, names...)
+	}
+	states := make([]blockupgrader.BlockState, 0, len(names))
+	for _, name := range names {
+		for _, properties := range s.downgradeProperties(name, state.Properties) {
+			states = append(states, blockupgrader.BlockState{Name: name, Properties: properties})
+		}
+	}
+	for _, remap := range s.remappedStates {
+		states = append(states, remap.downgrade(state)...)
+	}
+	return states
+}
+
+// renamed checks if the block with the name passed was renamed by the schema, meaning that no block with that
+// name can exist after the schema was applied, unless another block was renamed to it.
+func (s downgradeSchema) renamed(name string) bool {
+	for _, names := range s.oldNames {
+		for _, n := range names {
+			if n == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// downgradeProperties returns the properties a block with the old name passed may have had before its properties
+// were upgraded by the schema. Multiple sets of properties are returned if several old values of a property were
+// remapped to the same new value.
+func (s downgradeSchema) downgradeProperties(oldName string, properties map[string]any) []map[string]any {
+	properties = maps.Clone(properties)
+	for _, k := range s.addedProperties[oldName] {
+		delete(properties, k)
+	}
+	values := map[string][]any{}
+	for newKey, oldKey := range s.renamedProperties[oldName] {
+		v, ok := properties[newKey]
+		if !ok {
+			continue
+		}
+		delete(properties, newKey)
+		// Values of renamed properties are remapped under their old name, so they are not remapped again below.
+		values[oldKey] = s.downgradeValue(oldName, oldKey, v)
+	}
+	for k := range s.remappedPropertyValues[oldName] {
+		if _, ok := values[k]; ok {
+			continue
+		}
+		if v, ok := properties[k]; ok {
+			values[k] = s.downgradeValue(oldName, k, v)
+		}
+	}
+
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	all := []map[string]any{properties}
+	for _, k := range keys {
+		if len(values[k]) == 0 {
+			// The old value can't be known, so the property is left out.
+			for _, p := range all {
+				delete(p, k)
+			}
+			continue
+		}
+		expanded := make([]map[string]any, 0, len(all)*len(values[k]))
+		for _, v := range values[k] {
+			for _, p := range all {
+				p = maps.Clone(p)
+				p[k] = v
+				expanded = append(expanded, p)
+			}
+		}
+		all = expanded
+	}
+	return all
+}
+
+// downgradeValue returns the old values of the property of the block passed that were upgraded to the new value
+// passed. No values are returned if the old value can't be found.
+func (s downgradeSchema) downgradeValue(oldName, oldKey string, v any) []any {
+	remaps, ok := s.remappedPropertyValues[oldName][oldKey]
+	if !ok {
+		return []any{v}
+	}
+	var values []any
+	unchanged := true
+	for _, remap := range remaps {
+		old := remap.Old.value()
+		if remap.New.value() == v {
+			values = append(values, old)
+		}
+		if old == v || reflect.TypeOf(old) != reflect.TypeOf(v) {
+			// Values without a remap are left unchanged, but only values of the same type as the old values can
+			// have been left unchanged.
+			unchanged = false
+		}
+	}
+	if unchanged {
+		values = append(values, v)
+	}
+	return values
+}
+
+// downgrade returns the states that the state passed may have been remapped from, if any.
+func (r stateRemap) downgrade(state blockupgrader.BlockState) []blockupgrader.BlockState {
+	for k, v := range state.Properties {
+		if tag, ok := r.NewState[k]; (!ok || tag.value() != v) && !r.copied(k) {
+			return nil
+		}
+	}
+	for k, tag := range r.NewState {
+		if state.Properties[k] != tag.value() {
+			return nil
+		}
+	}
+	properties := make(map[string]any, len(r.OldState)+len(r.CopiedState)+1)
+	for k, tag := range r.OldState {
+		properties[k] = tag.value()
+	}
+	for _, k := range r.CopiedState {
+		if v, ok := state.Properties[k]; ok {
+			properties[k] = v
+		}
+	}
+
+	if r.NewFlattenedName == nil {
+		if state.Name != r.NewName {
+			return nil
+		}
+		return []blockupgrader.BlockState{{Name: r.oldName, Properties: properties}}
+	}
+	f := r.NewFlattenedName
+	if !strings.HasPrefix(state.Name, f.Prefix) || !strings.HasSuffix(state.Name, f.Suffix) || len(state.Name) <= len(f.Prefix)+len(f.Suffix) {
+		return nil
+	}
+	flattened := state.Name[len(f.Prefix) : len(state.Name)-len(f.Suffix)]
+	var values []string
+	if _, ok := f.FlattenedValueRemaps[flattened]; !ok {
+		values = append(values, flattened)
+	}
+	for old, v := range f.FlattenedValueRemaps {
+		if v == flattened {
+			values = append(values, old)
+		}
+	}
+	sort.Strings(values)
+
+	states := make([]blockupgrader.BlockState, 0, len(values))
+	for _, v := range values {
+		p := maps.Clone(properties)
+		p[f.FlattenedProperty] = v
+		states = append(states, blockupgrader.BlockState{Name: r.oldName, Properties: p})
+	}
+	return states
+}
+
+// copied checks if the property passed is copied from the old state to the new state.
+func (r stateRemap) copied(k string) bool {
+	for _, c := range r.CopiedState {
+		if c == k {
+			return true
+		}
+	}
+	return false
+}
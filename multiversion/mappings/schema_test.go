@@ -0,0 +1,76 @@
+package mappings
+
+import (
+	"bytes"
+	"maps"
+	"os"
+	"testing"
+
+	"github.com/df-mc/worldupgrader/blockupgrader"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+)
+
+// TestDowngradeSchemas tests that downgrading an upgraded block state produces the state it was upgraded from,
+// apart from properties of which the old value can't be known.
+func TestDowngradeSchemas(t *testing.T) {
+	for _, path := range []string{"../mv567/mappings/block_states.nbt", "../mv582/mappings/block_states.nbt"} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dec := nbt.NewDecoder(bytes.NewBuffer(data))
+		for {
+			var s blockupgrader.BlockState
+			if err := dec.Decode(&s); err != nil {
+				break
+			}
+			upgraded := blockupgrader.Upgrade(blockupgrader.BlockState{Name: s.Name, Properties: maps.Clone(s.Properties), Version: s.Version})
+			if !containsState(downgradeState(upgraded, s.Version), s) {
+				t.Fatalf("%v: downgrading %v %v did not produce %v %v", path, upgraded.Name, upgraded.Properties, s.Name, s.Properties)
+			}
+		}
+	}
+}
+
+// TestDowngradeClosestState tests that states with property values that do not exist in an older version are
+// downgraded to the closest state of the same block.
+func TestDowngradeClosestState(t *testing.T) {
+	data, err := os.ReadFile("../mv582/mappings/block_states.nbt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := blockMapping(data, false)
+
+	rid, exact := m.downgradeState("minecraft:calibrated_sculk_sensor", map[string]any{"minecraft:cardinal_direction": "west", "sculk_sensor_phase": int32(2)})
+	if name, properties, _ := m.RuntimeIDToState(rid); name != "minecraft:calibrated_sculk_sensor" || properties["minecraft:cardinal_direction"] != "west" || exact {
+		t.Errorf("expected a west facing minecraft:calibrated_sculk_sensor, got %v %v (exact: %v)", name, properties, exact)
+	}
+	if _, exact := m.downgradeState("minecraft:pumpkin", map[string]any{"minecraft:cardinal_direction": "west"}); !exact {
+		t.Errorf("expected an exact counterpart of minecraft:pumpkin")
+	}
+	rid, _ = m.downgradeState("minecraft:crafter", map[string]any{"crafting": byte(0), "orientation": "north_up", "triggered_bit": byte(0)})
+	if name, _, _ := m.RuntimeIDToState(rid); name != "minecraft:info_update" {
+		t.Errorf("expected minecraft:crafter to be downgraded to minecraft:info_update, got %v", name)
+	}
+}
+
+// containsState checks if any of the states passed has the same name as want and only properties that want has
+// with the same value.
+func containsState(states []blockupgrader.BlockState, want blockupgrader.BlockState) bool {
+	for _, s := range states {
+		if s.Name != want.Name {
+			continue
+		}
+		match := true
+		for k, v := range s.Properties {
+			if want.Properties[k] != v {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
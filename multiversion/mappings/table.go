@@ -0,0 +1,120 @@
+package mappings
+
+import (
+	"math"
+
+	"github.com/oomph-ac/mv/multiversion/latest"
+)
+
+// noItem is the value of an itemTable for item runtime IDs that do not have a counterpart.
+const noItem = math.MinInt32
+
+// translationTables holds dense tables to translate the runtime IDs of blocks and items between the latest
+// version and the version of an MVMapping. They are precomputed from the block states and item names of both
+// versions, so that translating a runtime ID does not need to hash block states or look up names.
+type translationTables struct {
+	// blockUpgrade is indexed by legacy block runtime IDs and blockDowngrade by latest block runtime IDs.
+	blockUpgrade, blockDowngrade []uint32
+	// itemUpgrade is indexed by legacy item runtime IDs and itemDowngrade by latest item runtime IDs.
+	itemUpgrade, itemDowngrade itemTable
+	// latestAirRID is the runtime ID of air in the latest version.
+	latestAirRID uint32
+}
+
+// itemTable is a dense table of item runtime IDs, indexed by runtime IDs starting at offset.
+type itemTable struct {
+	offset int32
+	ids    []int32
+}
+
+// newItemTable creates an itemTable that holds room for all runtime IDs in the map passed and sets each of them
+// to the result of f, or noItem if f returns false.
+func newItemTable(ids map[int32]string, f func(id int32, name string) (int32, bool)) itemTable {
+	if len(ids) == 0 {
+		return itemTable{}
+	}
+	minID, maxID := int32(math.MaxInt32), int32(math.MinInt32)
+	for id := range ids {
+		minID, maxID = min(minID, id), max(maxID, id)
+	}
+	t := itemTable{offset: minID, ids: make([]int32, int64(maxID)-int64(minID)+1)}
+	for i := range t.ids {
+		t.ids[i] = noItem
+	}
+	for id, name := range ids {
+		if translated, ok := f(id, name); ok {
+			t.ids[id-minID] = translated
+		}
+	}
+	return t
+}
+
+// lookup looks up the runtime ID passed in the table. False is returned if the runtime ID has no counterpart.
+func (t itemTable) lookup(id int32) (int32, bool) {
+	i := int64(id) - int64(t.offset)
+	if i < 0 || i >= int64(len(t.ids)) || t.ids[i] == noItem {
+		return 0, false
+	}
+	return t.ids[i], true
+}
+
+// newTranslationTables precomputes the translationTables of the MVMapping passed.
+func newTranslationTables(m MVMapping) *translationTables {
+	latestAirRID, _ := latest.StateToRuntimeID("minecraft:air", nil)
+	t := &translationTables{
+		blockUpgrade:   make([]uint32, len(m.blocks)),
+		blockDowngrade: make([]uint32, latest.BlockCount()),
+		latestAirRID:   latestAirRID,
+	}
+	for rid := range t.blockUpgrade {
+		name, properties, _ := m.RuntimeIDToState(uint32(rid))
+		latestRID, ok := latest.StateToRuntimeID(name, properties)
+		if !ok {
+			latestRID = latestAirRID
+		}
+		t.blockUpgrade[rid] = latestRID
+	}
+	for rid := range t.blockDowngrade {
+		name, properties, _ := latest.RuntimeIDToState(uint32(rid))
+		t.blockDowngrade[rid] = m.StateToRuntimeID(name, properties)
+	}
+
+	t.itemUpgrade = newItemTable(m.itemRuntimeIDsToNames, func(_ int32, name string) (int32, bool) {
+		return latest.ItemNameToRuntimeID(name)
+	})
+	t.itemDowngrade = newItemTable(latest.ItemRuntimeIDs(), func(_ int32, name string) (int32, bool) {
+		return m.ItemIDByName(name)
+	})
+	return t
+}
+
+// UpgradeBlockRuntimeID translates a legacy block runtime ID to a block runtime ID of the latest version. Runtime
+// IDs without a counterpart translate to air.
+func (m MVMapping) UpgradeBlockRuntimeID(runtimeID uint32) uint32 {
+	if runtimeID >= uint32(len(m.tables.blockUpgrade)) {
+		return m.tables.latestAirRID
+	}
+	return m.tables.blockUpgrade[runtimeID]
+}
+
+// DowngradeBlockRuntimeID translates a block runtime ID of the latest version to a legacy block runtime ID.
+// Runtime IDs of unknown blocks translate to air and blocks that do not exist in the legacy version translate to
+// minecraft:info_update.
+func (m MVMapping) DowngradeBlockRuntimeID(runtimeID uint32) uint32 {
+	if runtimeID >= uint32(len(m.tables.blockDowngrade)) {
+		return m.LegacyAirRID
+	}
+	return m.tables.blockDowngrade[runtimeID]
+}
+
+// UpgradeItemRuntimeID translates a legacy item runtime ID to an item runtime ID of the latest version. False is
+// returned if the item does not exist in the latest version.
+func (m MVMapping) UpgradeItemRuntimeID(runtimeID int32) (int32, bool) {
+	return m.tables.itemUpgrade.lookup(runtimeID)
+}
+
+// DowngradeItemRuntimeID translates an item runtime ID of the latest version to a legacy item runtime ID. False
+// is returned if the item does not exist in the legacy version.
+func (m MVMapping) DowngradeItemRuntimeID(runtimeID int32) (int32, bool) {
+	return m.tables.itemDowngrade.lookup(runtimeID)
+}
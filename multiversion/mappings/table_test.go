@@ -0,0 +1,128 @@
+package mappings_test
+
+import (
+	"testing"
+
+	"github.com/oomph-ac/mv/multiversion/latest"
+	"github.com/oomph-ac/mv/multiversion/mappings"
+	"github.com/oomph-ac/mv/multiversion/mv589"
+	"github.com/oomph-ac/mv/multiversion/mv662"
+)
+
+// TestTranslationTables tests that the dense translation tables of a mapping translate every block and item the
+// same way as looking up their states and names does.
+func TestTranslationTables(t *testing.T) {
+	for name, m := range map[string]mappings.MVMapping{"mv589": mv589.Mapping(), "mv662": mv662.Mapping()} {
+		t.Run(name, func(t *testing.T) {
+			for rid := uint32(0); rid < latest.BlockCount()+2; rid++ {
+				if got, want := m.DowngradeBlockRuntimeID(rid), downgradeBlockByState(m, rid); got != want {
+					t.Fatalf("downgrade block %v: expected %v, got %v", rid, want, got)
+				}
+			}
+			for rid := uint32(0); rid < uint32(len(m.Blocks()))+2; rid++ {
+				if got, want := m.UpgradeBlockRuntimeID(rid), upgradeBlockByState(m, rid); got != want {
+					t.Fatalf("upgrade block %v: expected %v, got %v", rid, want, got)
+				}
+			}
+			for id := range latest.ItemRuntimeIDs() {
+				got, ok := m.DowngradeItemRuntimeID(id)
+				want, wantOK := downgradeItemByName(m, id)
+				if ok != wantOK || (ok && got != want) {
+					t.Fatalf("downgrade item %v: expected %v (%v), got %v (%v)", id, want, wantOK, got, ok)
+				}
+			}
+			for _, it := range m.Items() {
+				got, ok := m.UpgradeItemRuntimeID(int32(it.RuntimeID))
+				want, wantOK := upgradeItemByName(m, int32(it.RuntimeID))
+				if ok != wantOK || (ok && got != want) {
+					t.Fatalf("upgrade item %v: expected %v (%v), got %v (%v)", it.RuntimeID, want, wantOK, got, ok)
+				}
+			}
+			if _, ok := m.UpgradeItemRuntimeID(1 << 20); ok {
+				t.Errorf("expected unknown item not to be upgraded")
+			}
+		})
+	}
+}
+
+func BenchmarkDowngradeBlockRuntimeID(b *testing.B) {
+	m, n := mv589.Mapping(), latest.BlockCount()
+	b.Run("Table", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			m.DowngradeBlockRuntimeID(uint32(i) % n)
+		}
+	})
+	b.Run("State", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			downgradeBlockByState(m, uint32(i)%n)
+		}
+	})
+}
+
+func BenchmarkUpgradeBlockRuntimeID(b *testing.B) {
+	m := mv589.Mapping()
+	n := uint32(len(m.Blocks()))
+	b.Run("Table", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			m.UpgradeBlockRuntimeID(uint32(i) % n)
+		}
+	})
+	b.Run("State", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			upgradeBlockByState(m, uint32(i)%n)
+		}
+	})
+}
+
+func BenchmarkDowngradeItemRuntimeID(b *testing.B) {
+	m := mv589.Mapping()
+	ids := make([]int32, 0, len(latest.ItemRuntimeIDs()))
+	for id := range latest.ItemRuntimeIDs() {
+		ids = append(ids, id)
+	}
+	b.Run("Table", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			m.DowngradeItemRuntimeID(ids[i%len(ids)])
+		}
+	})
+	b.Run("Name", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			downgradeItemByName(m, ids[i%len(ids)])
+		}
+	})
+}
+
+// downgradeBlockByState downgrades a block runtime ID by looking up its block state.
+func downgradeBlockByState(m mappings.MVMapping, rid uint32) uint32 {
+	if rid >= latest.BlockCount() {
+		return m.LegacyAirRID
+	}
+	name, properties, _ := latest.RuntimeIDToState(rid)
+	return m.StateToRuntimeID(name, properties)
+}
+
+// upgradeBlockByState upgrades a block runtime ID by looking up its block state.
+func upgradeBlockByState(m mappings.MVMapping, rid uint32) uint32 {
+	air, _ := latest.StateToRuntimeID("minecraft:air", nil)
+	if rid >= uint32(len(m.Blocks())) {
+		return air
+	}
+	name, properties, _ := m.RuntimeIDToState(rid)
+	latestRID, ok := latest.StateToRuntimeID(name, properties)
+	if !ok {
+		return air
+	}
+	return latestRID
+}
+
+// downgradeItemByName downgrades an item runtime ID by looking up its name.
+func downgradeItemByName(m mappings.MVMapping, id int32) (int32, bool) {
+	name, _ := latest.ItemRuntimeIDToName(id)
+	return m.ItemIDByName(name)
+}
+
+// upgradeItemByName upgrades an item runtime ID by looking up its name.
+func upgradeItemByName(m mappings.MVMapping, id int32) (int32, bool) {
+	name, _ := m.ItemNameByID(id)
+	return latest.ItemNameToRuntimeID(name)
+}
@@ -0,0 +1,137 @@
+package packet
+
+import (
+	"github.com/google/uuid"
+	v630packet "github.com/oomph-ac/mv/multiversion/mv630/packet"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+const (
+	PlayerListActionAdd = iota
+	PlayerListActionRemove
+)
+
+// PlayerList is sent by the server to update the client-side player list in the in-game menu screen. It shows
+// the icon of each player if the correct XUID is written in the packet.
+// Sending the PlayerList packet is obligatory when sending an AddPlayer packet. The added player will not
+// show up to a client if it has not been added to the player list, because several properties of the player
+// are obtained from the player list, such as the skin.
+type PlayerList struct {
+	// ActionType is the action to execute upon the player list. The entries that follow specify which entries
+	// are added or removed from the player list.
+	ActionType byte
+	// Entries is a list of all player list entries that should be added/removed from the player list,
+	// depending on the ActionType set.
+	Entries []PlayerListEntry
+}
+
+// ID ...
+func (*PlayerList) ID() uint32 {
+	return packet.IDPlayerList
+}
+
+func (pk *PlayerList) Marshal(io protocol.IO) {
+	io.Uint8(&pk.ActionType)
+	switch pk.ActionType {
+	case PlayerListActionAdd:
+		protocol.Slice(io, &pk.Entries)
+	case PlayerListActionRemove:
+		protocol.FuncIOSlice(io, &pk.Entries, playerListRemoveEntry)
+	default:
+		io.UnknownEnumOption(pk.ActionType, "player list action type")
+	}
+
+	if pk.ActionType == PlayerListActionAdd {
+		for i := 0; i < len(pk.Entries); i++ {
+			io.Bool(&pk.Entries[i].Skin.Trusted)
+		}
+	}
+}
+
+// playerListRemoveEntry encodes/decodes a PlayerListEntry for removal from the list.
+func playerListRemoveEntry(r protocol.IO, x *PlayerListEntry) {
+	r.UUID(&x.UUID)
+}
+
+// PlayerListEntry is an entry found in the PlayerList packet. It represents a single player using the UUID
+// found in the entry, and contains several properties such as the skin.
+type PlayerListEntry struct {
+	// UUID is the UUID of the player as sent in the Login packet when the client joined the server. It must
+	// match this UUID exactly for the correct XBOX Live icon to show up in the list.
+	UUID uuid.UUID
+	// EntityUniqueID is the unique entity ID of the player. This ID typically stays consistent during the
+	// lifetime of a world, but servers often send the runtime ID for this.
+	EntityUniqueID int64
+	// Username is the username that is shown in the player list of the player that obtains a PlayerList
+	// packet with this entry. It does not have to be the same as the actual username of the player.
+	Username string
+	// XUID is the XBOX Live user ID of the player, which will remain consistent as long as the player is
+	// logged in with the XBOX Live account.
+	XUID string
+	// PlatformChatID is an identifier only set for particular platforms when chatting (presumably only for
+	// Nintendo Switch). It is otherwise an empty string, and is used to decide which players are able to
+	// chat with each other.
+	PlatformChatID string
+	// BuildPlatform is the platform of the player as sent by that player in the Login packet.
+	BuildPlatform int32
+	// Skin is the skin of the player that should be added to the player list. Once sent here, it will not
+	// have to be sent again.
+	Skin Skin
+	// Teacher is a Minecraft: Education Edition field. It specifies if the player to be added to the player
+	// list is a teacher.
+	Teacher bool
+	// Host specifies if the player that is added to the player list is the host of the game.
+	Host bool
+}
+
+// Marshal encodes/decodes a PlayerListEntry.
+func (x *PlayerListEntry) Marshal(r protocol.IO) {
+	r.UUID(&x.UUID)
+	r.Varint64(&x.EntityUniqueID)
+	r.String(&x.Username)
+	r.String(&x.XUID)
+	r.String(&x.PlatformChatID)
+	r.Int32(&x.BuildPlatform)
+	protocol.Single(r, &x.Skin)
+	r.Bool(&x.Teacher)
+	r.Bool(&x.Host)
+}
+
+func DowngradePlayerEntries(entries []v630packet.PlayerListEntry) []PlayerListEntry {
+	new := make([]PlayerListEntry, 0, len(entries))
+	for _, e := range entries {
+		new = append(new, PlayerListEntry{
+			UUID:           e.UUID,
+			EntityUniqueID: e.EntityUniqueID,
+			Username:       e.Username,
+			XUID:           e.XUID,
+			PlatformChatID: e.PlatformChatID,
+			BuildPlatform:  e.BuildPlatform,
+			Skin:           DowngradeSkin(e.Skin),
+			Teacher:        e.Teacher,
+			Host:           e.Host,
+		})
+	}
+
+	return new
+}
+
+func UpgradePlayerEntries(entries []PlayerListEntry) []v630packet.PlayerListEntry {
+	new := make([]v630packet.PlayerListEntry, 0, len(entries))
+	for _, e := range entries {
+		new = append(new, v630packet.PlayerListEntry{
+			UUID:           e.UUID,
+			EntityUniqueID: e.EntityUniqueID,
+			Username:       e.Username,
+			XUID:           e.XUID,
+			PlatformChatID: e.PlatformChatID,
+			BuildPlatform:  e.BuildPlatform,
+			Skin:           UpgradeSkin(e.Skin),
+			Teacher:        e.Teacher,
+			Host:           e.Host,
+		})
+	}
+
+	return new
+}
@@ -0,0 +1,20 @@
+package packet
+
+import (
+	v568packet "github.com/oomph-ac/mv/multiversion/mv568/packet"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+func NewClientPool() packet.Pool {
+	pool := v568packet.NewClientPool()
+	pool[packet.IDPlayerSkin] = func() packet.Packet { return &PlayerSkin{} }
+	return pool
+}
+
+func NewServerPool() packet.Pool {
+	pool := v568packet.NewServerPool()
+	pool[packet.IDPlayerSkin] = func() packet.Packet { return &PlayerSkin{} }
+	pool[packet.IDPlayerList] = func() packet.Packet { return &PlayerList{} }
+
+	return pool
+}
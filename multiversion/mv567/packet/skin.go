@@ -0,0 +1,182 @@
+package packet
+
+import (
+	"fmt"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// Skin represents the skin of a player as sent over network. The skin holds a texture and a model, and
+// optional animations which may be present when the skin is created using persona or bought from the
+// marketplace.
+type Skin struct {
+	// SkinID is a unique ID produced for the skin, for example 'c18e65aa-7b21-4637-9b63-8ad63622ef01_Alex'
+	// for the default Alex skin.
+	SkinID string
+	// PlayFabID is the PlayFab ID produced for the skin. PlayFab is the company that hosts the Marketplace,
+	// skins and other related features from the game. This ID is the ID of the skin used to store the skin
+	// inside of PlayFab.
+	PlayFabID string
+	// SkinResourcePatch is a JSON encoded object holding some fields that point to the geometry that the
+	// skin has.
+	// The JSON object that this holds specifies the way that the geometry of animations and the default skin
+	// of the player are combined.
+	SkinResourcePatch []byte
+	// SkinImageWidth and SkinImageHeight hold the dimensions of the skin image. Note that these are not the
+	// dimensions in bytes, but in pixels.
+	SkinImageWidth, SkinImageHeight uint32
+	// SkinData is a byte slice of SkinImageWidth * SkinImageHeight bytes. It is an RGBA ordered byte
+	// representation of the skin pixels.
+	SkinData []byte
+	// Animations is a list of all animations that the skin has.
+	Animations []protocol.SkinAnimation
+	// CapeImageWidth and CapeImageHeight hold the dimensions of the cape image. Note that these are not the
+	// dimensions in bytes, but in pixels.
+	CapeImageWidth, CapeImageHeight uint32
+	// CapeData is a byte slice of 64*32*4 bytes. It is a RGBA ordered byte representation of the cape
+	// colours, much like the SkinData.
+	CapeData []byte
+	// SkinGeometry is a JSON encoded structure of the geometry data of a skin, containing properties
+	// such as bones, uv, pivot etc.
+	SkinGeometry []byte
+	// TODO: Find out what value AnimationData holds and when it does hold something.
+	AnimationData []byte
+	// GeometryDataEngineVersion ...
+	GeometryDataEngineVersion []byte
+	// PremiumSkin specifies if this is a skin that was purchased from the marketplace.
+	PremiumSkin bool
+	// PersonaSkin specifies if this is a skin that was created using the in-game skin creator.
+	PersonaSkin bool
+	// PersonaCapeOnClassicSkin specifies if the skin had a Persona cape (in-game skin creator cape) equipped
+	// on a classic skin.
+	PersonaCapeOnClassicSkin bool
+	// PrimaryUser ...
+	PrimaryUser bool
+	// CapeID is a unique identifier that identifies the cape. It usually holds a UUID in it.
+	CapeID string
+	// FullID is an ID that represents the skin in full. The actual functionality is unknown: The client
+	// does not seem to send a value for this.
+	FullID string
+	// SkinColour is a hex representation (including #) of the base colour of the skin. An example of the
+	// colour sent here is '#b37b62'.
+	SkinColour string
+	// ArmSize is the size of the arms of the player's model. This is either 'wide' (generally for male skins)
+	// or 'slim' (generally for female skins).
+	ArmSize string
+	// PersonaPieces is a list of all persona pieces that the skin is composed of.
+	PersonaPieces []protocol.PersonaPiece
+	// PieceTintColours is a list of specific tint colours for (some of) the persona pieces found in the list
+	// above.
+	PieceTintColours []protocol.PersonaPieceTintColour
+	// Trusted specifies if the skin is 'trusted'. No code should rely on this field, as any proxy or client
+	// can easily change it.
+	Trusted bool
+}
+
+func (x *Skin) Marshal(r protocol.IO) {
+	r.String(&x.SkinID)
+	r.String(&x.PlayFabID)
+	r.ByteSlice(&x.SkinResourcePatch)
+	r.Uint32(&x.SkinImageWidth)
+	r.Uint32(&x.SkinImageHeight)
+	r.ByteSlice(&x.SkinData)
+	protocol.SliceUint32Length(r, &x.Animations)
+	r.Uint32(&x.CapeImageWidth)
+	r.Uint32(&x.CapeImageHeight)
+	r.ByteSlice(&x.CapeData)
+	r.ByteSlice(&x.SkinGeometry)
+	r.ByteSlice(&x.GeometryDataEngineVersion)
+	r.ByteSlice(&x.AnimationData)
+	r.String(&x.CapeID)
+	r.String(&x.FullID)
+	r.String(&x.ArmSize)
+	r.String(&x.SkinColour)
+	protocol.SliceUint32Length(r, &x.PersonaPieces)
+	protocol.SliceUint32Length(r, &x.PieceTintColours)
+	if err := x.validate(); err != nil {
+		r.InvalidValue(fmt.Sprintf("Skin %v", x.SkinID), "serialised skin", err.Error())
+	}
+	r.Bool(&x.PremiumSkin)
+	r.Bool(&x.PersonaSkin)
+	r.Bool(&x.PersonaCapeOnClassicSkin)
+	r.Bool(&x.PrimaryUser)
+}
+
+// validate checks the skin and makes sure every one of its values are correct. It checks the image dimensions
+// and makes sure they match the image size of the skin, cape and the skin's animations.
+func (x *Skin) validate() error {
+	if x.SkinImageHeight*x.SkinImageWidth*4 != uint32(len(x.SkinData)) {
+		return fmt.Errorf("expected size of skin is %vx%v (%v bytes total), but got %v bytes", x.SkinImageWidth, x.SkinImageHeight, x.SkinImageHeight*x.SkinImageWidth*4, len(x.SkinData))
+	}
+	if x.CapeImageHeight*x.CapeImageWidth*4 != uint32(len(x.CapeData)) {
+		return fmt.Errorf("expected size of cape is %vx%v (%v bytes total), but got %v bytes", x.CapeImageWidth, x.CapeImageHeight, x.CapeImageHeight*x.CapeImageWidth*4, len(x.CapeData))
+	}
+	for i, animation := range x.Animations {
+		if animation.ImageHeight*animation.ImageWidth*4 != uint32(len(animation.ImageData)) {
+			return fmt.Errorf("expected size of animation %v is %vx%v (%v bytes total), but got %v bytes", i, animation.ImageWidth, animation.ImageHeight, animation.ImageHeight*animation.ImageWidth*4, len(animation.ImageData))
+		}
+	}
+	return nil
+}
+
+// DowngradeSkin converts a skin of the latest version to a 1.19.60 skin.
+func DowngradeSkin(s protocol.Skin) Skin {
+	return Skin{
+		SkinID:                    s.SkinID,
+		PlayFabID:                 s.PlayFabID,
+		SkinResourcePatch:         s.SkinResourcePatch,
+		SkinImageWidth:            s.SkinImageWidth,
+		SkinImageHeight:           s.SkinImageHeight,
+		SkinData:                  s.SkinData,
+		Animations:                s.Animations,
+		CapeImageWidth:            s.CapeImageWidth,
+		CapeImageHeight:           s.CapeImageHeight,
+		CapeData:                  s.CapeData,
+		SkinGeometry:              s.SkinGeometry,
+		AnimationData:             s.AnimationData,
+		GeometryDataEngineVersion: s.GeometryDataEngineVersion,
+		PremiumSkin:               s.PremiumSkin,
+		PersonaSkin:               s.PersonaSkin,
+		PersonaCapeOnClassicSkin:  s.PersonaCapeOnClassicSkin,
+		PrimaryUser:               s.PrimaryUser,
+		CapeID:                    s.CapeID,
+		FullID:                    s.FullID,
+		SkinColour:                s.SkinColour,
+		ArmSize:                   s.ArmSize,
+		PersonaPieces:             s.PersonaPieces,
+		PieceTintColours:          s.PieceTintColours,
+		Trusted:                   s.Trusted,
+	}
+}
+
+// UpgradeSkin converts a 1.19.60 skin to a skin of the latest version. 1.19.60 clients always override the
+// appearance of a player with the skin sent, so OverrideAppearance is set.
+func UpgradeSkin(s Skin) protocol.Skin {
+	return protocol.Skin{
+		SkinID:                    s.SkinID,
+		PlayFabID:                 s.PlayFabID,
+		SkinResourcePatch:         s.SkinResourcePatch,
+		SkinImageWidth:            s.SkinImageWidth,
+		SkinImageHeight:           s.SkinImageHeight,
+		SkinData:                  s.SkinData,
+		Animations:                s.Animations,
+		CapeImageWidth:            s.CapeImageWidth,
+		CapeImageHeight:           s.CapeImageHeight,
+		CapeData:                  s.CapeData,
+		SkinGeometry:              s.SkinGeometry,
+		AnimationData:             s.AnimationData,
+		GeometryDataEngineVersion: s.GeometryDataEngineVersion,
+		PremiumSkin:               s.PremiumSkin,
+		PersonaSkin:               s.PersonaSkin,
+		PersonaCapeOnClassicSkin:  s.PersonaCapeOnClassicSkin,
+		PrimaryUser:               s.PrimaryUser,
+		CapeID:                    s.CapeID,
+		FullID:                    s.FullID,
+		SkinColour:                s.SkinColour,
+		ArmSize:                   s.ArmSize,
+		PersonaPieces:             s.PersonaPieces,
+		PieceTintColours:          s.PieceTintColours,
+		Trusted:                   s.Trusted,
+		OverrideAppearance:        true,
+	}
+}
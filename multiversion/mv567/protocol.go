@@ -0,0 +1,118 @@
+package mv567
+
+import (
+	"sync"
+
+	"github.com/oomph-ac/mv/multiversion/capability"
+	"github.com/oomph-ac/mv/multiversion/mappings"
+	"github.com/oomph-ac/mv/multiversion/mv567/packet"
+	"github.com/oomph-ac/mv/multiversion/mv568"
+	"github.com/oomph-ac/mv/multiversion/util"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+
+	v630packet "github.com/oomph-ac/mv/multiversion/mv630/packet"
+	gtpacket "github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+var capabilities = sync.OnceValue(func() capability.Set {
+	return capability.Derive(packet.NewServerPool(), packet.NewClientPool(), Mapping())
+})
+
+type Protocol struct{}
+
+func (Protocol) ID() int32 {
+	return 567
+}
+
+func (Protocol) Ver() string {
+	return "1.19.60"
+}
+
+func (Protocol) NewReader(r minecraft.ByteReader, shieldID int32, enableLimits bool) protocol.IO {
+	return protocol.NewReader(r, shieldID, enableLimits)
+}
+
+func (Protocol) NewWriter(r minecraft.ByteWriter, shieldID int32) protocol.IO {
+	return protocol.NewWriter(r, shieldID)
+}
+
+func (Protocol) Packets(listener bool) gtpacket.Pool {
+	if listener {
+		return packet.NewClientPool()
+	}
+	return packet.NewServerPool()
+}
+
+func (Protocol) Encryption(key [32]byte) gtpacket.Encryption {
+	return gtpacket.NewCTREncryption(key[:])
+}
+
+func (Protocol) Mapping() mappings.MVMapping {
+	return Mapping()
+}
+
+func (Protocol) Capabilities() capability.Set {
+	return capabilities()
+}
+
+func (Protocol) ConvertToLatest(pk gtpacket.Packet, conn *minecraft.Conn) (packets []gtpacket.Packet) {
+	defer util.RecoverMalformed(conn, pk, &packets)
+	return util.UpgradePacket(conn, pk, Mapping(), Upgrade)
+}
+
+func (Protocol) ConvertFromLatest(pk gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
+	if downgraded, ok := util.DefaultDowngrade(conn, pk, Mapping()); ok {
+		return Downgrade([]gtpacket.Packet{downgraded}, conn)
+	}
+
+	return Downgrade([]gtpacket.Packet{pk}, conn)
+}
+
+func Upgrade(pks []gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
+	packets := make([]gtpacket.Packet, 0, len(pks))
+	for _, pk := range pks {
+		switch pk := pk.(type) {
+		case *packet.PlayerSkin:
+			packets = append(packets, &gtpacket.PlayerSkin{
+				UUID:        pk.UUID,
+				Skin:        packet.UpgradeSkin(pk.Skin),
+				NewSkinName: pk.NewSkinName,
+				OldSkinName: pk.OldSkinName,
+			})
+		case *packet.PlayerList:
+			packets = append(packets, &v630packet.PlayerList{
+				ActionType: pk.ActionType,
+				Entries:    packet.UpgradePlayerEntries(pk.Entries),
+			})
+		default:
+			packets = append(packets, pk)
+		}
+	}
+
+	return mv568.Upgrade(packets, conn)
+}
+
+func Downgrade(pks []gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
+	packets := []gtpacket.Packet{}
+	for _, pk := range mv568.Downgrade(pks, conn) {
+		switch pk := pk.(type) {
+		case *gtpacket.PlayerSkin:
+			packets = append(packets, &packet.PlayerSkin{
+				UUID:        pk.UUID,
+				Skin:        packet.DowngradeSkin(pk.Skin),
+				NewSkinName: pk.NewSkinName,
+				OldSkinName: pk.OldSkinName,
+			})
+		case *v630packet.PlayerList:
+			packets = append(packets, &packet.PlayerList{
+				ActionType: pk.ActionType,
+				Entries:    packet.DowngradePlayerEntries(pk.Entries),
+			})
+		default:
+			packets = append(packets, pk)
+		}
+	}
+
+	return packets
+}
@@ -0,0 +1,21 @@
+package packet
+
+import (
+	v575packet "github.com/oomph-ac/mv/multiversion/mv575/packet"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+func NewClientPool() packet.Pool {
+	pool := v575packet.NewClientPool()
+	pool[packet.IDPlayerAuthInput] = func() packet.Packet { return &PlayerAuthInput{} }
+	return pool
+}
+
+func NewServerPool() packet.Pool {
+	pool := v575packet.NewServerPool()
+	delete(pool, packet.IDCameraPresets)
+	delete(pool, packet.IDCameraInstruction)
+	delete(pool, packet.IDUnlockedRecipes)
+
+	return pool
+}
@@ -0,0 +1,133 @@
+package mv568
+
+import (
+	"sync"
+
+	"github.com/oomph-ac/mv/multiversion/capability"
+	"github.com/oomph-ac/mv/multiversion/mappings"
+	"github.com/oomph-ac/mv/multiversion/mv568/packet"
+	"github.com/oomph-ac/mv/multiversion/mv575"
+	"github.com/oomph-ac/mv/multiversion/util"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+
+	v582packet "github.com/oomph-ac/mv/multiversion/mv582/packet"
+	v630packet "github.com/oomph-ac/mv/multiversion/mv630/packet"
+	gtpacket "github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+var capabilities = sync.OnceValue(func() capability.Set {
+	return capability.Derive(packet.NewServerPool(), packet.NewClientPool(), Mapping())
+})
+
+type Protocol struct{}
+
+func (Protocol) ID() int32 {
+	return 568
+}
+
+func (Protocol) Ver() string {
+	return "1.19.63"
+}
+
+func (Protocol) NewReader(r minecraft.ByteReader, shieldID int32, enableLimits bool) protocol.IO {
+	return protocol.NewReader(r, shieldID, enableLimits)
+}
+
+func (Protocol) NewWriter(r minecraft.ByteWriter, shieldID int32) protocol.IO {
+	return protocol.NewWriter(r, shieldID)
+}
+
+func (Protocol) Packets(listener bool) gtpacket.Pool {
+	if listener {
+		return packet.NewClientPool()
+	}
+	return packet.NewServerPool()
+}
+
+func (Protocol) Encryption(key [32]byte) gtpacket.Encryption {
+	return gtpacket.NewCTREncryption(key[:])
+}
+
+func (Protocol) Mapping() mappings.MVMapping {
+	return Mapping()
+}
+
+func (Protocol) Capabilities() capability.Set {
+	return capabilities()
+}
+
+func (Protocol) ConvertToLatest(pk gtpacket.Packet, conn *minecraft.Conn) (packets []gtpacket.Packet) {
+	defer util.RecoverMalformed(conn, pk, &packets)
+	return util.UpgradePacket(conn, pk, Mapping(), Upgrade)
+}
+
+func (Protocol) ConvertFromLatest(pk gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
+	if downgraded, ok := util.DefaultDowngrade(conn, pk, Mapping()); ok {
+		return Downgrade([]gtpacket.Packet{downgraded}, conn)
+	}
+
+	return Downgrade([]gtpacket.Packet{pk}, conn)
+}
+
+func Upgrade(pks []gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
+	packets := make([]gtpacket.Packet, 0, len(pks))
+	for _, pk := range pks {
+		switch pk := pk.(type) {
+		case *packet.PlayerAuthInput:
+			packets = append(packets, &v630packet.PlayerAuthInput{
+				Pitch:               pk.Pitch,
+				Yaw:                 pk.Yaw,
+				Position:            pk.Position,
+				MoveVector:          pk.MoveVector,
+				HeadYaw:             pk.HeadYaw,
+				InputData:           pk.InputData,
+				InputMode:           pk.InputMode,
+				PlayMode:            pk.PlayMode,
+				InteractionModel:    pk.InteractionModel,
+				GazeDirection:       pk.GazeDirection,
+				Tick:                pk.Tick,
+				Delta:               pk.Delta,
+				ItemInteractionData: pk.ItemInteractionData,
+				ItemStackRequest:    pk.ItemStackRequest,
+				BlockActions:        pk.BlockActions,
+			})
+		default:
+			packets = append(packets, pk)
+		}
+	}
+
+	return mv575.Upgrade(packets, conn)
+}
+
+func Downgrade(pks []gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
+	packets := []gtpacket.Packet{}
+	for _, pk := range mv575.Downgrade(pks, conn) {
+		switch pk := pk.(type) {
+		case *v630packet.PlayerAuthInput:
+			packets = append(packets, &packet.PlayerAuthInput{
+				Pitch:               pk.Pitch,
+				Yaw:                 pk.Yaw,
+				Position:            pk.Position,
+				MoveVector:          pk.MoveVector,
+				HeadYaw:             pk.HeadYaw,
+				InputData:           pk.InputData,
+				InputMode:           pk.InputMode,
+				PlayMode:            pk.PlayMode,
+				InteractionModel:    pk.InteractionModel,
+				GazeDirection:       pk.GazeDirection,
+				Tick:                pk.Tick,
+				Delta:               pk.Delta,
+				ItemInteractionData: pk.ItemInteractionData,
+				ItemStackRequest:    pk.ItemStackRequest,
+				BlockActions:        pk.BlockActions,
+			})
+		case *gtpacket.CameraPresets, *gtpacket.CameraInstruction, *v582packet.UnlockedRecipes:
+			// These packets do not exist in 1.19.63.
+		default:
+			packets = append(packets, pk)
+		}
+	}
+
+	return packets
+}
@@ -0,0 +1,22 @@
+package mv568
+
+import (
+	"testing"
+
+	"github.com/oomph-ac/mv/multiversion/internal/conformance"
+	"github.com/oomph-ac/mv/multiversion/util"
+	"github.com/sandertv/gophertunnel/minecraft"
+	gtpacket "github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// TestConformance round-trips every packet overridden by the protocol through a downgrade and upgrade.
+func TestConformance(t *testing.T) {
+	conformance.Test(t, Protocol{})
+}
+
+// FuzzConvertToLatest fuzzes the conversion of packets read from a connection to the latest version.
+func FuzzConvertToLatest(f *testing.F) {
+	conformance.Fuzz(f, Protocol{}, func(pk gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
+		return util.UpgradePacket(conn, pk, Mapping(), Upgrade)
+	})
+}
@@ -0,0 +1,42 @@
+package packet
+
+import (
+	v649packet "github.com/oomph-ac/mv/multiversion/mv649/packet"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+const (
+	CommandArgTypeEquipmentSlots = 38
+	CommandArgTypeString         = 39
+	CommandArgTypeBlockPosition  = 47
+	CommandArgTypePosition       = 48
+	CommandArgTypeMessage        = 51
+	CommandArgTypeRawText        = 53
+	CommandArgTypeJSON           = 57
+	CommandArgTypeBlockStates    = 67
+	CommandArgTypeCommand        = 70
+)
+
+// CommandArgTypes maps every command argument type of 1.20.60 to the argument type of 1.19.70. Argument types
+// below 38 are the same in both versions.
+var CommandArgTypes = map[uint32]uint32{
+	protocol.CommandArgTypeInt:              protocol.CommandArgTypeInt,
+	protocol.CommandArgTypeFloat:            protocol.CommandArgTypeFloat,
+	protocol.CommandArgTypeValue:            protocol.CommandArgTypeValue,
+	protocol.CommandArgTypeWildcardInt:      protocol.CommandArgTypeWildcardInt,
+	protocol.CommandArgTypeOperator:         protocol.CommandArgTypeOperator,
+	protocol.CommandArgTypeCompareOperator:  protocol.CommandArgTypeCompareOperator,
+	protocol.CommandArgTypeTarget:           protocol.CommandArgTypeTarget,
+	protocol.CommandArgTypeWildcardTarget:   protocol.CommandArgTypeWildcardTarget,
+	protocol.CommandArgTypeFilepath:         protocol.CommandArgTypeFilepath,
+	protocol.CommandArgTypeIntegerRange:     protocol.CommandArgTypeIntegerRange,
+	v649packet.CommandArgTypeEquipmentSlots: CommandArgTypeEquipmentSlots,
+	v649packet.CommandArgTypeString:         CommandArgTypeString,
+	v649packet.CommandArgTypeBlockPosition:  CommandArgTypeBlockPosition,
+	v649packet.CommandArgTypePosition:       CommandArgTypePosition,
+	v649packet.CommandArgTypeMessage:        CommandArgTypeMessage,
+	v649packet.CommandArgTypeRawText:        CommandArgTypeRawText,
+	v649packet.CommandArgTypeJSON:           CommandArgTypeJSON,
+	v649packet.CommandArgTypeBlockStates:    CommandArgTypeBlockStates,
+	v649packet.CommandArgTypeCommand:        CommandArgTypeCommand,
+}
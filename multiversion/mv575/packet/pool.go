@@ -0,0 +1,22 @@
+package packet
+
+import (
+	v582packet "github.com/oomph-ac/mv/multiversion/mv582/packet"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+func NewClientPool() packet.Pool {
+	pool := v582packet.NewClientPool()
+	pool[packet.IDRequestChunkRadius] = func() packet.Packet { return &RequestChunkRadius{} }
+	return pool
+}
+
+func NewServerPool() packet.Pool {
+	pool := v582packet.NewServerPool()
+	pool[packet.IDStartGame] = func() packet.Packet { return &StartGame{} }
+	delete(pool, packet.IDOpenSign)
+	delete(pool, packet.IDTrimData)
+	delete(pool, packet.IDCompressedBiomeDefinitionList)
+
+	return pool
+}
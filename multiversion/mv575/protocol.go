@@ -0,0 +1,265 @@
+package mv575
+
+import (
+	"sync"
+
+	"github.com/oomph-ac/mv/multiversion/capability"
+	"github.com/oomph-ac/mv/multiversion/mappings"
+	"github.com/oomph-ac/mv/multiversion/mv575/packet"
+	"github.com/oomph-ac/mv/multiversion/mv582"
+	"github.com/oomph-ac/mv/multiversion/util"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+
+	v582packet "github.com/oomph-ac/mv/multiversion/mv582/packet"
+	v589packet "github.com/oomph-ac/mv/multiversion/mv589/packet"
+	gtpacket "github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+var capabilities = sync.OnceValue(func() capability.Set {
+	return capability.Derive(packet.NewServerPool(), packet.NewClientPool(), Mapping())
+})
+
+var (
+	// commandArgTypes translates the command argument types of 1.20.60 to those of 1.19.70, and
+	// legacyCommandArgTypes translates them back.
+	commandArgTypes       = util.CommandArgTypes(packet.CommandArgTypes)
+	legacyCommandArgTypes = commandArgTypes.Inverse()
+)
+
+type Protocol struct{}
+
+func (Protocol) ID() int32 {
+	return 575
+}
+
+func (Protocol) Ver() string {
+	return "1.19.70"
+}
+
+func (Protocol) NewReader(r minecraft.ByteReader, shieldID int32, enableLimits bool) protocol.IO {
+	return protocol.NewReader(r, shieldID, enableLimits)
+}
+
+func (Protocol) NewWriter(r minecraft.ByteWriter, shieldID int32) protocol.IO {
+	return protocol.NewWriter(r, shieldID)
+}
+
+func (Protocol) Packets(listener bool) gtpacket.Pool {
+	if listener {
+		return packet.NewClientPool()
+	}
+	return packet.NewServerPool()
+}
+
+func (Protocol) Encryption(key [32]byte) gtpacket.Encryption {
+	return gtpacket.NewCTREncryption(key[:])
+}
+
+func (Protocol) Mapping() mappings.MVMapping {
+	return Mapping()
+}
+
+func (Protocol) Capabilities() capability.Set {
+	return capabilities()
+}
+
+func (Protocol) ConvertToLatest(pk gtpacket.Packet, conn *minecraft.Conn) (packets []gtpacket.Packet) {
+	defer util.RecoverMalformed(conn, pk, &packets)
+	return util.UpgradePacket(conn, pk, Mapping(), Upgrade)
+}
+
+func (Protocol) ConvertFromLatest(pk gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
+	if downgraded, ok := util.DefaultDowngrade(conn, pk, Mapping()); ok {
+		return Downgrade([]gtpacket.Packet{downgraded}, conn)
+	}
+
+	return Downgrade([]gtpacket.Packet{pk}, conn)
+}
+
+func Upgrade(pks []gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
+	packets := make([]gtpacket.Packet, 0, len(pks))
+	for _, pk := range pks {
+		switch pk := pk.(type) {
+		case *packet.StartGame:
+			packets = append(packets, &v582packet.StartGame{
+				EntityUniqueID:                 pk.EntityUniqueID,
+				EntityRuntimeID:                pk.EntityRuntimeID,
+				PlayerGameMode:                 pk.PlayerGameMode,
+				PlayerPosition:                 pk.PlayerPosition,
+				Pitch:                          pk.Pitch,
+				Yaw:                            pk.Yaw,
+				WorldSeed:                      pk.WorldSeed,
+				SpawnBiomeType:                 pk.SpawnBiomeType,
+				UserDefinedBiomeName:           pk.UserDefinedBiomeName,
+				Dimension:                      pk.Dimension,
+				Generator:                      pk.Generator,
+				WorldGameMode:                  pk.WorldGameMode,
+				Difficulty:                     pk.Difficulty,
+				WorldSpawn:                     pk.WorldSpawn,
+				AchievementsDisabled:           pk.AchievementsDisabled,
+				EditorWorld:                    pk.EditorWorld,
+				DayCycleLockTime:               pk.DayCycleLockTime,
+				EducationEditionOffer:          pk.EducationEditionOffer,
+				EducationFeaturesEnabled:       pk.EducationFeaturesEnabled,
+				EducationProductID:             pk.EducationProductID,
+				RainLevel:                      pk.RainLevel,
+				LightningLevel:                 pk.LightningLevel,
+				ConfirmedPlatformLockedContent: pk.ConfirmedPlatformLockedContent,
+				MultiPlayerGame:                pk.MultiPlayerGame,
+				LANBroadcastEnabled:            pk.LANBroadcastEnabled,
+				XBLBroadcastMode:               pk.XBLBroadcastMode,
+				PlatformBroadcastMode:          pk.PlatformBroadcastMode,
+				CommandsEnabled:                pk.CommandsEnabled,
+				TexturePackRequired:            pk.TexturePackRequired,
+				GameRules:                      pk.GameRules,
+				Experiments:                    pk.Experiments,
+				ExperimentsPreviouslyToggled:   pk.ExperimentsPreviouslyToggled,
+				BonusChestEnabled:              pk.BonusChestEnabled,
+				StartWithMapEnabled:            pk.StartWithMapEnabled,
+				PlayerPermissions:              pk.PlayerPermissions,
+				ServerChunkTickRadius:          pk.ServerChunkTickRadius,
+				HasLockedBehaviourPack:         pk.HasLockedBehaviourPack,
+				HasLockedTexturePack:           pk.HasLockedTexturePack,
+				FromLockedWorldTemplate:        pk.FromLockedWorldTemplate,
+				MSAGamerTagsOnly:               pk.MSAGamerTagsOnly,
+				FromWorldTemplate:              pk.FromWorldTemplate,
+				WorldTemplateSettingsLocked:    pk.WorldTemplateSettingsLocked,
+				OnlySpawnV1Villagers:           pk.OnlySpawnV1Villagers,
+				PersonaDisabled:                pk.PersonaDisabled,
+				CustomSkinsDisabled:            pk.CustomSkinsDisabled,
+				EmoteChatMuted:                 pk.EmoteChatMuted,
+				BaseGameVersion:                pk.BaseGameVersion,
+				LimitedWorldWidth:              pk.LimitedWorldWidth,
+				LimitedWorldDepth:              pk.LimitedWorldDepth,
+				NewNether:                      pk.NewNether,
+				EducationSharedResourceURI:     pk.EducationSharedResourceURI,
+				ForceExperimentalGameplay:      pk.ForceExperimentalGameplay,
+				LevelID:                        pk.LevelID,
+				WorldName:                      pk.WorldName,
+				TemplateContentIdentity:        pk.TemplateContentIdentity,
+				Trial:                          pk.Trial,
+				PlayerMovementSettings:         pk.PlayerMovementSettings,
+				Time:                           pk.Time,
+				EnchantmentSeed:                pk.EnchantmentSeed,
+				Blocks:                         pk.Blocks,
+				Items:                          pk.Items,
+				MultiPlayerCorrelationID:       pk.MultiPlayerCorrelationID,
+				ServerAuthoritativeInventory:   pk.ServerAuthoritativeInventory,
+				GameVersion:                    pk.GameVersion,
+				PropertyData:                   pk.PropertyData,
+				ServerBlockStateChecksum:       pk.ServerBlockStateChecksum,
+				ClientSideGeneration:           pk.ClientSideGeneration,
+				WorldTemplateID:                pk.WorldTemplateID,
+				ChatRestrictionLevel:           pk.ChatRestrictionLevel,
+				DisablePlayerInteractions:      pk.DisablePlayerInteractions,
+			})
+		case *packet.RequestChunkRadius:
+			packets = append(packets, &gtpacket.RequestChunkRadius{
+				ChunkRadius:    pk.ChunkRadius,
+				MaxChunkRadius: pk.ChunkRadius,
+			})
+		case *v589packet.AvailableCommands:
+			pk.Commands = legacyCommandArgTypes.Commands(pk.Commands)
+			packets = append(packets, pk)
+		default:
+			packets = append(packets, pk)
+		}
+	}
+
+	return mv582.Upgrade(packets, conn)
+}
+
+func Downgrade(pks []gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
+	packets := []gtpacket.Packet{}
+	for _, pk := range mv582.Downgrade(pks, conn) {
+		switch pk := pk.(type) {
+		case *v582packet.StartGame:
+			packets = append(packets, &packet.StartGame{
+				EntityUniqueID:                 pk.EntityUniqueID,
+				EntityRuntimeID:                pk.EntityRuntimeID,
+				PlayerGameMode:                 pk.PlayerGameMode,
+				PlayerPosition:                 pk.PlayerPosition,
+				Pitch:                          pk.Pitch,
+				Yaw:                            pk.Yaw,
+				WorldSeed:                      pk.WorldSeed,
+				SpawnBiomeType:                 pk.SpawnBiomeType,
+				UserDefinedBiomeName:           pk.UserDefinedBiomeName,
+				Dimension:                      pk.Dimension,
+				Generator:                      pk.Generator,
+				WorldGameMode:                  pk.WorldGameMode,
+				Difficulty:                     pk.Difficulty,
+				WorldSpawn:                     pk.WorldSpawn,
+				AchievementsDisabled:           pk.AchievementsDisabled,
+				EditorWorld:                    pk.EditorWorld,
+				DayCycleLockTime:               pk.DayCycleLockTime,
+				EducationEditionOffer:          pk.EducationEditionOffer,
+				EducationFeaturesEnabled:       pk.EducationFeaturesEnabled,
+				EducationProductID:             pk.EducationProductID,
+				RainLevel:                      pk.RainLevel,
+				LightningLevel:                 pk.LightningLevel,
+				ConfirmedPlatformLockedContent: pk.ConfirmedPlatformLockedContent,
+				MultiPlayerGame:                pk.MultiPlayerGame,
+				LANBroadcastEnabled:            pk.LANBroadcastEnabled,
+				XBLBroadcastMode:               pk.XBLBroadcastMode,
+				PlatformBroadcastMode:          pk.PlatformBroadcastMode,
+				CommandsEnabled:                pk.CommandsEnabled,
+				TexturePackRequired:            pk.TexturePackRequired,
+				GameRules:                      pk.GameRules,
+				Experiments:                    pk.Experiments,
+				ExperimentsPreviouslyToggled:   pk.ExperimentsPreviouslyToggled,
+				BonusChestEnabled:              pk.BonusChestEnabled,
+				StartWithMapEnabled:            pk.StartWithMapEnabled,
+				PlayerPermissions:              pk.PlayerPermissions,
+				ServerChunkTickRadius:          pk.ServerChunkTickRadius,
+				HasLockedBehaviourPack:         pk.HasLockedBehaviourPack,
+				HasLockedTexturePack:           pk.HasLockedTexturePack,
+				FromLockedWorldTemplate:        pk.FromLockedWorldTemplate,
+				MSAGamerTagsOnly:               pk.MSAGamerTagsOnly,
+				FromWorldTemplate:              pk.FromWorldTemplate,
+				WorldTemplateSettingsLocked:    pk.WorldTemplateSettingsLocked,
+				OnlySpawnV1Villagers:           pk.OnlySpawnV1Villagers,
+				PersonaDisabled:                pk.PersonaDisabled,
+				CustomSkinsDisabled:            pk.CustomSkinsDisabled,
+				EmoteChatMuted:                 pk.EmoteChatMuted,
+				BaseGameVersion:                pk.BaseGameVersion,
+				LimitedWorldWidth:              pk.LimitedWorldWidth,
+				LimitedWorldDepth:              pk.LimitedWorldDepth,
+				NewNether:                      pk.NewNether,
+				EducationSharedResourceURI:     pk.EducationSharedResourceURI,
+				ForceExperimentalGameplay:      pk.ForceExperimentalGameplay,
+				LevelID:                        pk.LevelID,
+				WorldName:                      pk.WorldName,
+				TemplateContentIdentity:        pk.TemplateContentIdentity,
+				Trial:                          pk.Trial,
+				PlayerMovementSettings:         pk.PlayerMovementSettings,
+				Time:                           pk.Time,
+				EnchantmentSeed:                pk.EnchantmentSeed,
+				Blocks:                         pk.Blocks,
+				Items:                          pk.Items,
+				MultiPlayerCorrelationID:       pk.MultiPlayerCorrelationID,
+				ServerAuthoritativeInventory:   pk.ServerAuthoritativeInventory,
+				GameVersion:                    pk.GameVersion,
+				PropertyData:                   pk.PropertyData,
+				ServerBlockStateChecksum:       pk.ServerBlockStateChecksum,
+				ClientSideGeneration:           pk.ClientSideGeneration,
+				WorldTemplateID:                pk.WorldTemplateID,
+				ChatRestrictionLevel:           pk.ChatRestrictionLevel,
+				DisablePlayerInteractions:      pk.DisablePlayerInteractions,
+			})
+		case *gtpacket.RequestChunkRadius:
+			packets = append(packets, &packet.RequestChunkRadius{
+				ChunkRadius: pk.ChunkRadius,
+			})
+		case *v589packet.AvailableCommands:
+			pk.Commands = commandArgTypes.Commands(pk.Commands)
+			packets = append(packets, pk)
+		case *gtpacket.OpenSign, *gtpacket.TrimData, *gtpacket.CompressedBiomeDefinitionList:
+			// These packets do not exist in 1.19.70.
+		default:
+			packets = append(packets, pk)
+		}
+	}
+
+	return packets
+}
@@ -0,0 +1,21 @@
+package packet
+
+import (
+	v589packet "github.com/oomph-ac/mv/multiversion/mv589/packet"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+func NewClientPool() packet.Pool {
+	pool := v589packet.NewClientPool()
+	pool[packet.IDEmote] = func() packet.Packet { return &Emote{} }
+	return pool
+}
+
+func NewServerPool() packet.Pool {
+	pool := v589packet.NewServerPool()
+	pool[packet.IDStartGame] = func() packet.Packet { return &StartGame{} }
+	pool[packet.IDEmote] = func() packet.Packet { return &Emote{} }
+	pool[packet.IDUnlockedRecipes] = func() packet.Packet { return &UnlockedRecipes{} }
+
+	return pool
+}
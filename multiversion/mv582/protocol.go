@@ -0,0 +1,320 @@
+package mv582
+
+import (
+	"bytes"
+	"maps"
+	"sync"
+
+	"github.com/oomph-ac/mv/multiversion/capability"
+	"github.com/oomph-ac/mv/multiversion/mappings"
+	"github.com/oomph-ac/mv/multiversion/mv582/packet"
+	"github.com/oomph-ac/mv/multiversion/mv589"
+	"github.com/oomph-ac/mv/multiversion/util"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sirupsen/logrus"
+
+	v594packet "github.com/oomph-ac/mv/multiversion/mv594/packet"
+	v630packet "github.com/oomph-ac/mv/multiversion/mv630/packet"
+	gtpacket "github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+var capabilities = sync.OnceValue(func() capability.Set {
+	return capability.Derive(packet.NewServerPool(), packet.NewClientPool(), Mapping())
+})
+
+type Protocol struct{}
+
+func (Protocol) ID() int32 {
+	return 582
+}
+
+func (Protocol) Ver() string {
+	return "1.19.80"
+}
+
+func (Protocol) NewReader(r minecraft.ByteReader, shieldID int32, enableLimits bool) protocol.IO {
+	return protocol.NewReader(r, shieldID, enableLimits)
+}
+
+func (Protocol) NewWriter(r minecraft.ByteWriter, shieldID int32) protocol.IO {
+	return protocol.NewWriter(r, shieldID)
+}
+
+func (Protocol) Packets(listener bool) gtpacket.Pool {
+	if listener {
+		return packet.NewClientPool()
+	}
+	return packet.NewServerPool()
+}
+
+func (Protocol) Encryption(key [32]byte) gtpacket.Encryption {
+	return gtpacket.NewCTREncryption(key[:])
+}
+
+func (Protocol) Mapping() mappings.MVMapping {
+	return Mapping()
+}
+
+func (Protocol) Capabilities() capability.Set {
+	return capabilities()
+}
+
+func (Protocol) ConvertToLatest(pk gtpacket.Packet, conn *minecraft.Conn) (packets []gtpacket.Packet) {
+	defer util.RecoverMalformed(conn, pk, &packets)
+	return util.UpgradePacket(conn, pk, Mapping(), Upgrade)
+}
+
+func (Protocol) ConvertFromLatest(pk gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
+	if downgraded, ok := util.DefaultDowngrade(conn, pk, Mapping()); ok {
+		return Downgrade([]gtpacket.Packet{downgraded}, conn)
+	}
+
+	return Downgrade([]gtpacket.Packet{pk}, conn)
+}
+
+func Upgrade(pks []gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
+	packets := make([]gtpacket.Packet, 0, len(pks))
+	for _, pk := range pks {
+		switch pk := pk.(type) {
+		case *packet.StartGame:
+			packets = append(packets, &v594packet.StartGame{
+				EntityUniqueID:                 pk.EntityUniqueID,
+				EntityRuntimeID:                pk.EntityRuntimeID,
+				PlayerGameMode:                 pk.PlayerGameMode,
+				PlayerPosition:                 pk.PlayerPosition,
+				Pitch:                          pk.Pitch,
+				Yaw:                            pk.Yaw,
+				WorldSeed:                      pk.WorldSeed,
+				SpawnBiomeType:                 pk.SpawnBiomeType,
+				UserDefinedBiomeName:           pk.UserDefinedBiomeName,
+				Dimension:                      pk.Dimension,
+				Generator:                      pk.Generator,
+				WorldGameMode:                  pk.WorldGameMode,
+				Difficulty:                     pk.Difficulty,
+				WorldSpawn:                     pk.WorldSpawn,
+				AchievementsDisabled:           pk.AchievementsDisabled,
+				EditorWorld:                    pk.EditorWorld,
+				CreatedInEditor:                pk.CreatedInEditor,
+				ExportedFromEditor:             pk.ExportedFromEditor,
+				DayCycleLockTime:               pk.DayCycleLockTime,
+				EducationEditionOffer:          pk.EducationEditionOffer,
+				EducationFeaturesEnabled:       pk.EducationFeaturesEnabled,
+				EducationProductID:             pk.EducationProductID,
+				RainLevel:                      pk.RainLevel,
+				LightningLevel:                 pk.LightningLevel,
+				ConfirmedPlatformLockedContent: pk.ConfirmedPlatformLockedContent,
+				MultiPlayerGame:                pk.MultiPlayerGame,
+				LANBroadcastEnabled:            pk.LANBroadcastEnabled,
+				XBLBroadcastMode:               pk.XBLBroadcastMode,
+				PlatformBroadcastMode:          pk.PlatformBroadcastMode,
+				CommandsEnabled:                pk.CommandsEnabled,
+				TexturePackRequired:            pk.TexturePackRequired,
+				GameRules:                      pk.GameRules,
+				Experiments:                    pk.Experiments,
+				ExperimentsPreviouslyToggled:   pk.ExperimentsPreviouslyToggled,
+				BonusChestEnabled:              pk.BonusChestEnabled,
+				StartWithMapEnabled:            pk.StartWithMapEnabled,
+				PlayerPermissions:              pk.PlayerPermissions,
+				ServerChunkTickRadius:          pk.ServerChunkTickRadius,
+				HasLockedBehaviourPack:         pk.HasLockedBehaviourPack,
+				HasLockedTexturePack:           pk.HasLockedTexturePack,
+				FromLockedWorldTemplate:        pk.FromLockedWorldTemplate,
+				MSAGamerTagsOnly:               pk.MSAGamerTagsOnly,
+				FromWorldTemplate:              pk.FromWorldTemplate,
+				WorldTemplateSettingsLocked:    pk.WorldTemplateSettingsLocked,
+				OnlySpawnV1Villagers:           pk.OnlySpawnV1Villagers,
+				PersonaDisabled:                pk.PersonaDisabled,
+				CustomSkinsDisabled:            pk.CustomSkinsDisabled,
+				EmoteChatMuted:                 pk.EmoteChatMuted,
+				BaseGameVersion:                pk.BaseGameVersion,
+				LimitedWorldWidth:              pk.LimitedWorldWidth,
+				LimitedWorldDepth:              pk.LimitedWorldDepth,
+				NewNether:                      pk.NewNether,
+				EducationSharedResourceURI:     pk.EducationSharedResourceURI,
+				ForceExperimentalGameplay:      pk.ForceExperimentalGameplay,
+				LevelID:                        pk.LevelID,
+				WorldName:                      pk.WorldName,
+				TemplateContentIdentity:        pk.TemplateContentIdentity,
+				Trial:                          pk.Trial,
+				PlayerMovementSettings:         pk.PlayerMovementSettings,
+				Time:                           pk.Time,
+				EnchantmentSeed:                pk.EnchantmentSeed,
+				Blocks:                         pk.Blocks,
+				Items:                          pk.Items,
+				MultiPlayerCorrelationID:       pk.MultiPlayerCorrelationID,
+				ServerAuthoritativeInventory:   pk.ServerAuthoritativeInventory,
+				GameVersion:                    pk.GameVersion,
+				PropertyData:                   pk.PropertyData,
+				ServerBlockStateChecksum:       pk.ServerBlockStateChecksum,
+				ClientSideGeneration:           pk.ClientSideGeneration,
+				WorldTemplateID:                pk.WorldTemplateID,
+				ChatRestrictionLevel:           pk.ChatRestrictionLevel,
+				DisablePlayerInteractions:      pk.DisablePlayerInteractions,
+				UseBlockNetworkIDHashes:        pk.UseBlockNetworkIDHashes,
+			})
+		case *packet.Emote:
+			packets = append(packets, &gtpacket.Emote{
+				EntityRuntimeID: pk.EntityRuntimeID,
+				EmoteID:         pk.EmoteID,
+				XUID:            conn.IdentityData().XUID,
+				PlatformID:      conn.ClientData().PlatformOnlineID,
+				Flags:           pk.Flags,
+			})
+		case *packet.UnlockedRecipes:
+			unlockType := uint32(gtpacket.UnlockedRecipesTypeInitiallyUnlocked)
+			if pk.NewUnlocks {
+				unlockType = gtpacket.UnlockedRecipesTypeNewlyUnlocked
+			}
+			packets = append(packets, &gtpacket.UnlockedRecipes{
+				UnlockType: unlockType,
+				Recipes:    pk.Recipes,
+			})
+		case *gtpacket.BlockActorData:
+			blockEntity := maps.Clone(pk.NBTData)
+			upgradeSign(blockEntity)
+			packets = append(packets, &gtpacket.BlockActorData{
+				Position: pk.Position,
+				NBTData:  blockEntity,
+			})
+		default:
+			packets = append(packets, pk)
+		}
+	}
+
+	return mv589.Upgrade(packets, conn)
+}
+
+func Downgrade(pks []gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
+	packets := []gtpacket.Packet{}
+	for _, pk := range mv589.Downgrade(pks, conn) {
+		switch pk := pk.(type) {
+		case *v594packet.StartGame:
+			packets = append(packets, &packet.StartGame{
+				EntityUniqueID:                 pk.EntityUniqueID,
+				EntityRuntimeID:                pk.EntityRuntimeID,
+				PlayerGameMode:                 pk.PlayerGameMode,
+				PlayerPosition:                 pk.PlayerPosition,
+				Pitch:                          pk.Pitch,
+				Yaw:                            pk.Yaw,
+				WorldSeed:                      pk.WorldSeed,
+				SpawnBiomeType:                 pk.SpawnBiomeType,
+				UserDefinedBiomeName:           pk.UserDefinedBiomeName,
+				Dimension:                      pk.Dimension,
+				Generator:                      pk.Generator,
+				WorldGameMode:                  pk.WorldGameMode,
+				Difficulty:                     pk.Difficulty,
+				WorldSpawn:                     pk.WorldSpawn,
+				AchievementsDisabled:           pk.AchievementsDisabled,
+				EditorWorld:                    pk.EditorWorld,
+				CreatedInEditor:                pk.CreatedInEditor,
+				ExportedFromEditor:             pk.ExportedFromEditor,
+				DayCycleLockTime:               pk.DayCycleLockTime,
+				EducationEditionOffer:          pk.EducationEditionOffer,
+				EducationFeaturesEnabled:       pk.EducationFeaturesEnabled,
+				EducationProductID:             pk.EducationProductID,
+				RainLevel:                      pk.RainLevel,
+				LightningLevel:                 pk.LightningLevel,
+				ConfirmedPlatformLockedContent: pk.ConfirmedPlatformLockedContent,
+				MultiPlayerGame:                pk.MultiPlayerGame,
+				LANBroadcastEnabled:            pk.LANBroadcastEnabled,
+				XBLBroadcastMode:               pk.XBLBroadcastMode,
+				PlatformBroadcastMode:          pk.PlatformBroadcastMode,
+				CommandsEnabled:                pk.CommandsEnabled,
+				TexturePackRequired:            pk.TexturePackRequired,
+				GameRules:                      pk.GameRules,
+				Experiments:                    pk.Experiments,
+				ExperimentsPreviouslyToggled:   pk.ExperimentsPreviouslyToggled,
+				BonusChestEnabled:              pk.BonusChestEnabled,
+				StartWithMapEnabled:            pk.StartWithMapEnabled,
+				PlayerPermissions:              pk.PlayerPermissions,
+				ServerChunkTickRadius:          pk.ServerChunkTickRadius,
+				HasLockedBehaviourPack:         pk.HasLockedBehaviourPack,
+				HasLockedTexturePack:           pk.HasLockedTexturePack,
+				FromLockedWorldTemplate:        pk.FromLockedWorldTemplate,
+				MSAGamerTagsOnly:               pk.MSAGamerTagsOnly,
+				FromWorldTemplate:              pk.FromWorldTemplate,
+				WorldTemplateSettingsLocked:    pk.WorldTemplateSettingsLocked,
+				OnlySpawnV1Villagers:           pk.OnlySpawnV1Villagers,
+				PersonaDisabled:                pk.PersonaDisabled,
+				CustomSkinsDisabled:            pk.CustomSkinsDisabled,
+				EmoteChatMuted:                 pk.EmoteChatMuted,
+				BaseGameVersion:                pk.BaseGameVersion,
+				LimitedWorldWidth:              pk.LimitedWorldWidth,
+				LimitedWorldDepth:              pk.LimitedWorldDepth,
+				NewNether:                      pk.NewNether,
+				EducationSharedResourceURI:     pk.EducationSharedResourceURI,
+				ForceExperimentalGameplay:      pk.ForceExperimentalGameplay,
+				LevelID:                        pk.LevelID,
+				WorldName:                      pk.WorldName,
+				TemplateContentIdentity:        pk.TemplateContentIdentity,
+				Trial:                          pk.Trial,
+				PlayerMovementSettings:         pk.PlayerMovementSettings,
+				Time:                           pk.Time,
+				EnchantmentSeed:                pk.EnchantmentSeed,
+				Blocks:                         util.DowngradeBlockDefinitions(pk.Blocks, util.MolangVersion1_19_60),
+				Items:                          pk.Items,
+				MultiPlayerCorrelationID:       pk.MultiPlayerCorrelationID,
+				ServerAuthoritativeInventory:   pk.ServerAuthoritativeInventory,
+				GameVersion:                    pk.GameVersion,
+				PropertyData:                   pk.PropertyData,
+				ServerBlockStateChecksum:       pk.ServerBlockStateChecksum,
+				ClientSideGeneration:           pk.ClientSideGeneration,
+				WorldTemplateID:                pk.WorldTemplateID,
+				ChatRestrictionLevel:           pk.ChatRestrictionLevel,
+				DisablePlayerInteractions:      pk.DisablePlayerInteractions,
+				UseBlockNetworkIDHashes:        pk.UseBlockNetworkIDHashes,
+			})
+		case *gtpacket.Emote:
+			packets = append(packets, &packet.Emote{
+				EntityRuntimeID: pk.EntityRuntimeID,
+				EmoteID:         pk.EmoteID,
+				Flags:           pk.Flags,
+			})
+		case *gtpacket.UnlockedRecipes:
+			if pk.UnlockType == gtpacket.UnlockedRecipesTypeRemoveUnlocked || pk.UnlockType == gtpacket.UnlockedRecipesTypeRemoveAllUnlocked {
+				// Recipes can't be locked again in 1.19.80.
+				continue
+			}
+			packets = append(packets, &packet.UnlockedRecipes{
+				NewUnlocks: pk.UnlockType == gtpacket.UnlockedRecipesTypeNewlyUnlocked,
+				Recipes:    pk.Recipes,
+			})
+		case *gtpacket.BlockActorData:
+			blockEntity := maps.Clone(pk.NBTData)
+			downgradeSign(blockEntity)
+			packets = append(packets, &gtpacket.BlockActorData{
+				Position: pk.Position,
+				NBTData:  blockEntity,
+			})
+		case *v630packet.LevelChunk:
+			// Chunks without signs are not decoded at all.
+			if !pk.CacheEnabled && bytes.Contains(pk.RawPayload, []byte("Sign")) {
+				payload, err := util.TranslateChunkBlockEntities(conn, pk.RawPayload, pk.SubChunkCount, signTranslationKey, downgradeSign)
+				if err != nil {
+					logrus.Error(err)
+				} else {
+					pk.RawPayload = payload
+				}
+			}
+			packets = append(packets, pk)
+		case *gtpacket.SubChunk:
+			for i, entry := range pk.SubChunkEntries {
+				if entry.Result != protocol.SubChunkResultSuccess || !bytes.Contains(entry.RawPayload, []byte("Sign")) {
+					continue
+				}
+				payload, err := util.TranslateSubChunkBlockEntities(conn, entry.RawPayload, pk.CacheEnabled, signTranslationKey, downgradeSign)
+				if err != nil {
+					logrus.Error(err)
+					continue
+				}
+				pk.SubChunkEntries[i].RawPayload = payload
+			}
+			packets = append(packets, pk)
+		default:
+			packets = append(packets, pk)
+		}
+	}
+
+	return packets
+}
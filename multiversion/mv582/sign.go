@@ -0,0 +1,68 @@
+package mv582
+
+// signTranslationKey identifies the translation of sign block entities in chunks shared between connections.
+const signTranslationKey = 582
+
+// isSign checks if the block entity passed is a sign or hanging sign.
+func isSign(blockEntity map[string]any) bool {
+	id, _ := blockEntity["id"].(string)
+	return id == "Sign" || id == "HangingSign"
+}
+
+// downgradeSign converts the NBT of a sign in the 1.20 format, which holds text for both sides of the sign, to
+// the format of 1.19.80 and earlier, which only has a single side. The back side of the sign is lost.
+func downgradeSign(blockEntity map[string]any) {
+	if !isSign(blockEntity) {
+		return
+	}
+	front, ok := blockEntity["FrontText"].(map[string]any)
+	if !ok {
+		return
+	}
+	delete(blockEntity, "FrontText")
+	delete(blockEntity, "BackText")
+	delete(blockEntity, "IsWaxed")
+
+	blockEntity["Text"], _ = front["Text"].(string)
+	blockEntity["TextOwner"], _ = front["TextOwner"].(string)
+	blockEntity["SignTextColor"], _ = front["SignTextColor"].(int32)
+	glowing, _ := front["IgnoreLighting"].(byte)
+	blockEntity["IgnoreLighting"], blockEntity["TextIgnoreLegacyBugResolved"] = glowing, glowing
+}
+
+// upgradeSign converts the NBT of a sign in the format of 1.19.80 and earlier to the 1.20 format. The text of
+// the sign is put on its front side, while its back side is left empty.
+func upgradeSign(blockEntity map[string]any) {
+	if !isSign(blockEntity) {
+		return
+	}
+	if _, ok := blockEntity["FrontText"]; ok {
+		return
+	}
+	text, _ := blockEntity["Text"].(string)
+	owner, _ := blockEntity["TextOwner"].(string)
+	colour, ok := blockEntity["SignTextColor"].(int32)
+	if !ok {
+		// Signs are black by default.
+		colour = -16777216
+	}
+	ignoreLighting, _ := blockEntity["IgnoreLighting"].(byte)
+	bugResolved, _ := blockEntity["TextIgnoreLegacyBugResolved"].(byte)
+	for _, k := range []string{"Text", "TextOwner", "SignTextColor", "IgnoreLighting", "TextIgnoreLegacyBugResolved"} {
+		delete(blockEntity, k)
+	}
+
+	blockEntity["IsWaxed"] = byte(0)
+	blockEntity["FrontText"] = map[string]any{
+		"Text":           text,
+		"TextOwner":      owner,
+		"SignTextColor":  colour,
+		"IgnoreLighting": ignoreLighting & bugResolved,
+	}
+	blockEntity["BackText"] = map[string]any{
+		"Text":           "",
+		"TextOwner":      "",
+		"SignTextColor":  int32(-16777216),
+		"IgnoreLighting": byte(0),
+	}
+}
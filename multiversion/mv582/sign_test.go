@@ -0,0 +1,79 @@
+package mv582
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/oomph-ac/mv/multiversion/chunk"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	gtpacket "github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// TestDowngradeSubChunkSign tests that signs in sub chunks are converted to the single sided format.
+func TestDowngradeSubChunkSign(t *testing.T) {
+	sign := map[string]any{
+		"id":      "Sign",
+		"IsWaxed": byte(0),
+		"FrontText": map[string]any{
+			"Text":           "front",
+			"TextOwner":      "",
+			"SignTextColor":  int32(-1),
+			"IgnoreLighting": byte(1),
+		},
+		"BackText": map[string]any{"Text": "back", "TextOwner": "", "SignTextColor": int32(0), "IgnoreLighting": byte(0)},
+		"x":        int32(1), "y": int32(2), "z": int32(3),
+	}
+	sub := chunk.EncodeSubChunk(chunk.NewSubChunk(0), chunk.NetworkEncoding, world.Overworld.Range(), 4)
+	payload := append(append([]byte(nil), sub...), marshal(t, sign)...)
+
+	packets := Downgrade([]gtpacket.Packet{&gtpacket.SubChunk{SubChunkEntries: []protocol.SubChunkEntry{
+		{Result: protocol.SubChunkResultSuccess, RawPayload: payload},
+	}}}, &minecraft.Conn{})
+	if len(packets) != 1 {
+		t.Fatalf("expected 1 packet, got %v", len(packets))
+	}
+	raw := packets[0].(*gtpacket.SubChunk).SubChunkEntries[0].RawPayload
+	if !bytes.HasPrefix(raw, sub) {
+		t.Fatalf("sub chunk was modified")
+	}
+	var downgraded map[string]any
+	if err := nbt.Unmarshal(raw[len(sub):], &downgraded); err != nil {
+		t.Fatal(err)
+	}
+	if downgraded["Text"] != "front" || downgraded["IgnoreLighting"] != byte(1) || downgraded["x"] != int32(1) {
+		t.Errorf("unexpected downgraded sign: %v", downgraded)
+	}
+	if _, ok := downgraded["FrontText"]; ok {
+		t.Errorf("expected FrontText to be removed: %v", downgraded)
+	}
+}
+
+// TestUpgradeSign tests that signs edited by a client are converted to the double sided format.
+func TestUpgradeSign(t *testing.T) {
+	legacy := map[string]any{"id": "Sign", "Text": "hello", "TextOwner": "", "x": int32(1), "y": int32(2), "z": int32(3)}
+	packets := Upgrade([]gtpacket.Packet{&gtpacket.BlockActorData{NBTData: legacy}}, &minecraft.Conn{})
+	if len(packets) != 1 {
+		t.Fatalf("expected 1 packet, got %v", len(packets))
+	}
+	upgraded := packets[0].(*gtpacket.BlockActorData).NBTData
+	front, _ := upgraded["FrontText"].(map[string]any)
+	back, _ := upgraded["BackText"].(map[string]any)
+	if front["Text"] != "hello" || back["Text"] != "" {
+		t.Errorf("unexpected upgraded sign: %v", upgraded)
+	}
+	if _, ok := legacy["FrontText"]; ok {
+		t.Errorf("the NBT of the packet passed was modified")
+	}
+}
+
+// marshal encodes the block entity passed using the network little endian encoding.
+func marshal(t *testing.T, blockEntity map[string]any) []byte {
+	data, err := nbt.Marshal(blockEntity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
@@ -0,0 +1,30 @@
+package packet
+
+import (
+	v649packet "github.com/oomph-ac/mv/multiversion/mv649/packet"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// CommandArgTypes maps the command argument types of 1.20.10 to the argument types of 1.20.0. The argument types
+// did not change between the versions.
+var CommandArgTypes = map[uint32]uint32{
+	protocol.CommandArgTypeInt:              protocol.CommandArgTypeInt,
+	protocol.CommandArgTypeFloat:            protocol.CommandArgTypeFloat,
+	protocol.CommandArgTypeValue:            protocol.CommandArgTypeValue,
+	protocol.CommandArgTypeWildcardInt:      protocol.CommandArgTypeWildcardInt,
+	protocol.CommandArgTypeOperator:         protocol.CommandArgTypeOperator,
+	protocol.CommandArgTypeCompareOperator:  protocol.CommandArgTypeCompareOperator,
+	protocol.CommandArgTypeTarget:           protocol.CommandArgTypeTarget,
+	protocol.CommandArgTypeWildcardTarget:   protocol.CommandArgTypeWildcardTarget,
+	protocol.CommandArgTypeFilepath:         protocol.CommandArgTypeFilepath,
+	protocol.CommandArgTypeIntegerRange:     protocol.CommandArgTypeIntegerRange,
+	v649packet.CommandArgTypeEquipmentSlots: v649packet.CommandArgTypeEquipmentSlots,
+	v649packet.CommandArgTypeString:         v649packet.CommandArgTypeString,
+	v649packet.CommandArgTypeBlockPosition:  v649packet.CommandArgTypeBlockPosition,
+	v649packet.CommandArgTypePosition:       v649packet.CommandArgTypePosition,
+	v649packet.CommandArgTypeMessage:        v649packet.CommandArgTypeMessage,
+	v649packet.CommandArgTypeRawText:        v649packet.CommandArgTypeRawText,
+	v649packet.CommandArgTypeJSON:           v649packet.CommandArgTypeJSON,
+	v649packet.CommandArgTypeBlockStates:    v649packet.CommandArgTypeBlockStates,
+	v649packet.CommandArgTypeCommand:        v649packet.CommandArgTypeCommand,
+}
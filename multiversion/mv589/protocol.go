@@ -1,8 +1,13 @@
 package mv589
 
 import (
+	"sync"
+
+	"github.com/oomph-ac/mv/multiversion/capability"
+	"github.com/oomph-ac/mv/multiversion/mappings"
 	"github.com/oomph-ac/mv/multiversion/mv589/packet"
 	"github.com/oomph-ac/mv/multiversion/mv594"
+	v594packet "github.com/oomph-ac/mv/multiversion/mv594/packet"
 	"github.com/oomph-ac/mv/multiversion/util"
 	"github.com/sandertv/gophertunnel/minecraft"
 	"github.com/sandertv/gophertunnel/minecraft/protocol"
@@ -10,6 +15,17 @@ import (
 	gtpacket "github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 )
 
+var capabilities = sync.OnceValue(func() capability.Set {
+	return capability.Derive(packet.NewServerPool(), packet.NewClientPool(), Mapping())
+})
+
+var (
+	// commandArgTypes translates the command argument types of 1.20.10 to those of 1.20.0, and
+	// legacyCommandArgTypes translates them back.
+	commandArgTypes       = util.CommandArgTypes(packet.CommandArgTypes)
+	legacyCommandArgTypes = commandArgTypes.Inverse()
+)
+
 type Protocol struct{}
 
 func (Protocol) ID() int32 {
@@ -30,29 +46,30 @@ func (Protocol) NewWriter(w minecraft.ByteWriter, shieldID int32) protocol.IO {
 
 func (Protocol) Packets(listener bool) gtpacket.Pool {
 	if listener {
-		return packet.NewServerPool()
+		return packet.NewClientPool()
 	}
-	return packet.NewClientPool()
+	return packet.NewServerPool()
 }
 
 func (Protocol) Encryption(key [32]byte) gtpacket.Encryption {
 	return gtpacket.NewCTREncryption(key[:])
 }
 
-func (Protocol) ConvertToLatest(pk gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
-	if upgraded, ok := util.DefaultUpgrade(conn, pk, Mapping); ok {
-		if upgraded == nil {
-			return []gtpacket.Packet{}
-		}
+func (Protocol) Mapping() mappings.MVMapping {
+	return Mapping()
+}
 
-		return Upgrade([]gtpacket.Packet{upgraded}, conn)
-	}
+func (Protocol) Capabilities() capability.Set {
+	return capabilities()
+}
 
-	return Upgrade([]gtpacket.Packet{pk}, conn)
+func (Protocol) ConvertToLatest(pk gtpacket.Packet, conn *minecraft.Conn) (packets []gtpacket.Packet) {
+	defer util.RecoverMalformed(conn, pk, &packets)
+	return util.UpgradePacket(conn, pk, Mapping(), Upgrade)
 }
 
 func (Protocol) ConvertFromLatest(pk gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
-	if downgraded, ok := util.DefaultDowngrade(conn, pk, Mapping); ok {
+	if downgraded, ok := util.DefaultDowngrade(conn, pk, Mapping()); ok {
 		return Downgrade([]gtpacket.Packet{downgraded}, conn)
 	}
 
@@ -60,19 +77,39 @@ func (Protocol) ConvertFromLatest(pk gtpacket.Packet, conn *minecraft.Conn) []gt
 }
 
 func Upgrade(pks []gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
-	return mv594.Upgrade(pks, conn)
+	packets := make([]gtpacket.Packet, 0, len(pks))
+	for _, pk := range pks {
+		switch pk := pk.(type) {
+		case *packet.AvailableCommands:
+			packets = append(packets, &gtpacket.AvailableCommands{
+				EnumValues:   pk.EnumValues,
+				Suffixes:     pk.Suffixes,
+				Enums:        pk.Enums,
+				Commands:     legacyCommandArgTypes.Commands(pk.Commands),
+				DynamicEnums: pk.DynamicEnums,
+				Constraints:  pk.Constraints,
+			})
+		default:
+			packets = append(packets, pk)
+		}
+	}
+
+	return mv594.Upgrade(packets, conn)
 }
 
 func Downgrade(pks []gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
 	packets := []gtpacket.Packet{}
 	for _, pk := range mv594.Downgrade(pks, conn) {
 		switch pk := pk.(type) {
+		case *v594packet.StartGame:
+			pk.Blocks = util.DowngradeBlockDefinitions(pk.Blocks, util.MolangVersion1_20_0)
+			packets = append(packets, pk)
 		case *gtpacket.AvailableCommands:
 			packets = append(packets, &packet.AvailableCommands{
 				EnumValues:   pk.EnumValues,
 				Suffixes:     pk.Suffixes,
 				Enums:        pk.Enums,
-				Commands:     packet.DowngradeCommands(pk.Commands),
+				Commands:     commandArgTypes.Commands(pk.Commands),
 				DynamicEnums: pk.DynamicEnums,
 				Constraints:  pk.Constraints,
 			})
@@ -1,6 +1,10 @@
 package mv594
 
 import (
+	"sync"
+
+	"github.com/oomph-ac/mv/multiversion/capability"
+	"github.com/oomph-ac/mv/multiversion/mappings"
 	"github.com/oomph-ac/mv/multiversion/mv594/packet"
 	"github.com/oomph-ac/mv/multiversion/mv618"
 	"github.com/oomph-ac/mv/multiversion/util"
@@ -12,6 +16,10 @@ import (
 	gtpacket "github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 )
 
+var capabilities = sync.OnceValue(func() capability.Set {
+	return capability.Derive(packet.NewServerPool(), packet.NewClientPool(), Mapping())
+})
+
 type Protocol struct{}
 
 func (Protocol) ID() int32 {
@@ -32,29 +40,30 @@ func (Protocol) NewWriter(w minecraft.ByteWriter, shieldID int32) protocol.IO {
 
 func (Protocol) Packets(listener bool) gtpacket.Pool {
 	if listener {
-		return packet.NewServerPool()
+		return packet.NewClientPool()
 	}
-	return packet.NewClientPool()
+	return packet.NewServerPool()
 }
 
 func (Protocol) Encryption(key [32]byte) gtpacket.Encryption {
 	return gtpacket.NewCTREncryption(key[:])
 }
 
-func (Protocol) ConvertToLatest(pk gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
-	if updated, ok := util.DefaultUpgrade(conn, pk, Mapping); ok {
-		if updated == nil {
-			return []gtpacket.Packet{}
-		}
+func (Protocol) Mapping() mappings.MVMapping {
+	return Mapping()
+}
 
-		return Upgrade([]gtpacket.Packet{updated}, conn)
-	}
+func (Protocol) Capabilities() capability.Set {
+	return capabilities()
+}
 
-	return Upgrade([]gtpacket.Packet{pk}, conn)
+func (Protocol) ConvertToLatest(pk gtpacket.Packet, conn *minecraft.Conn) (packets []gtpacket.Packet) {
+	defer util.RecoverMalformed(conn, pk, &packets)
+	return util.UpgradePacket(conn, pk, Mapping(), Upgrade)
 }
 
 func (Protocol) ConvertFromLatest(pk gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
-	if downgraded, ok := util.DefaultDowngrade(conn, pk, Mapping); ok {
+	if downgraded, ok := util.DefaultDowngrade(conn, pk, Mapping()); ok {
 		return Downgrade([]gtpacket.Packet{downgraded}, conn)
 	}
 
@@ -62,7 +71,100 @@ func (Protocol) ConvertFromLatest(pk gtpacket.Packet, conn *minecraft.Conn) []gt
 }
 
 func Upgrade(pks []gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
-	return mv618.Upgrade(pks, conn)
+	packets := make([]gtpacket.Packet, 0, len(pks))
+	for _, pk := range pks {
+		switch pk := pk.(type) {
+		case *packet.StartGame:
+			packets = append(packets, &v662packet.StartGame{
+				EntityUniqueID:                 pk.EntityUniqueID,
+				EntityRuntimeID:                pk.EntityRuntimeID,
+				PlayerGameMode:                 pk.PlayerGameMode,
+				PlayerPosition:                 pk.PlayerPosition,
+				Pitch:                          pk.Pitch,
+				Yaw:                            pk.Yaw,
+				WorldSeed:                      pk.WorldSeed,
+				SpawnBiomeType:                 pk.SpawnBiomeType,
+				UserDefinedBiomeName:           pk.UserDefinedBiomeName,
+				Dimension:                      pk.Dimension,
+				Generator:                      pk.Generator,
+				WorldGameMode:                  pk.WorldGameMode,
+				Difficulty:                     pk.Difficulty,
+				WorldSpawn:                     pk.WorldSpawn,
+				AchievementsDisabled:           pk.AchievementsDisabled,
+				EditorWorldType:                editorWorldType(pk.EditorWorld),
+				CreatedInEditor:                pk.CreatedInEditor,
+				ExportedFromEditor:             pk.ExportedFromEditor,
+				DayCycleLockTime:               pk.DayCycleLockTime,
+				EducationEditionOffer:          pk.EducationEditionOffer,
+				EducationFeaturesEnabled:       pk.EducationFeaturesEnabled,
+				EducationProductID:             pk.EducationProductID,
+				RainLevel:                      pk.RainLevel,
+				LightningLevel:                 pk.LightningLevel,
+				ConfirmedPlatformLockedContent: pk.ConfirmedPlatformLockedContent,
+				MultiPlayerGame:                pk.MultiPlayerGame,
+				LANBroadcastEnabled:            pk.LANBroadcastEnabled,
+				XBLBroadcastMode:               pk.XBLBroadcastMode,
+				PlatformBroadcastMode:          pk.PlatformBroadcastMode,
+				CommandsEnabled:                pk.CommandsEnabled,
+				TexturePackRequired:            pk.TexturePackRequired,
+				GameRules:                      pk.GameRules,
+				Experiments:                    pk.Experiments,
+				ExperimentsPreviouslyToggled:   pk.ExperimentsPreviouslyToggled,
+				BonusChestEnabled:              pk.BonusChestEnabled,
+				StartWithMapEnabled:            pk.StartWithMapEnabled,
+				PlayerPermissions:              pk.PlayerPermissions,
+				ServerChunkTickRadius:          pk.ServerChunkTickRadius,
+				HasLockedBehaviourPack:         pk.HasLockedBehaviourPack,
+				HasLockedTexturePack:           pk.HasLockedTexturePack,
+				FromLockedWorldTemplate:        pk.FromLockedWorldTemplate,
+				MSAGamerTagsOnly:               pk.MSAGamerTagsOnly,
+				FromWorldTemplate:              pk.FromWorldTemplate,
+				WorldTemplateSettingsLocked:    pk.WorldTemplateSettingsLocked,
+				OnlySpawnV1Villagers:           pk.OnlySpawnV1Villagers,
+				PersonaDisabled:                pk.PersonaDisabled,
+				CustomSkinsDisabled:            pk.CustomSkinsDisabled,
+				EmoteChatMuted:                 pk.EmoteChatMuted,
+				BaseGameVersion:                pk.BaseGameVersion,
+				LimitedWorldWidth:              pk.LimitedWorldWidth,
+				LimitedWorldDepth:              pk.LimitedWorldDepth,
+				NewNether:                      pk.NewNether,
+				EducationSharedResourceURI:     pk.EducationSharedResourceURI,
+				ForceExperimentalGameplay:      pk.ForceExperimentalGameplay,
+				LevelID:                        pk.LevelID,
+				WorldName:                      pk.WorldName,
+				TemplateContentIdentity:        pk.TemplateContentIdentity,
+				Trial:                          pk.Trial,
+				PlayerMovementSettings:         pk.PlayerMovementSettings,
+				Time:                           pk.Time,
+				EnchantmentSeed:                pk.EnchantmentSeed,
+				Blocks:                         pk.Blocks,
+				Items:                          pk.Items,
+				MultiPlayerCorrelationID:       pk.MultiPlayerCorrelationID,
+				ServerAuthoritativeInventory:   pk.ServerAuthoritativeInventory,
+				GameVersion:                    pk.GameVersion,
+				PropertyData:                   pk.PropertyData,
+				ServerBlockStateChecksum:       pk.ServerBlockStateChecksum,
+				ClientSideGeneration:           pk.ClientSideGeneration,
+				WorldTemplateID:                pk.WorldTemplateID,
+				ChatRestrictionLevel:           pk.ChatRestrictionLevel,
+				DisablePlayerInteractions:      pk.DisablePlayerInteractions,
+				UseBlockNetworkIDHashes:        pk.UseBlockNetworkIDHashes,
+				ServerAuthoritativeSound:       pk.ServerAuthoritativeSound,
+			})
+		case *packet.ResourcePacksInfo:
+			packets = append(packets, &v649packet.ResourcePacksInfo{
+				TexturePackRequired: pk.TexturePackRequired,
+				HasScripts:          pk.HasScripts,
+				BehaviourPacks:      pk.BehaviourPacks,
+				TexturePacks:        pk.TexturePacks,
+				ForcingServerPacks:  pk.ForcingServerPacks,
+			})
+		default:
+			packets = append(packets, pk)
+		}
+	}
+
+	return mv618.Upgrade(packets, conn)
 }
 
 func Downgrade(pks []gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
@@ -161,3 +263,11 @@ func Downgrade(pks []gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
 
 	return packets
 }
+
+// editorWorldType returns the editor world type of the latest version for the editor world flag of 1.20.10.
+func editorWorldType(editorWorld bool) int32 {
+	if editorWorld {
+		return gtpacket.EditorWorldTypeProject
+	}
+	return gtpacket.EditorWorldTypeNotEditor
+}
@@ -1,14 +1,23 @@
 package mv618
 
 import (
+	"sync"
+
+	"github.com/oomph-ac/mv/multiversion/capability"
+	"github.com/oomph-ac/mv/multiversion/mappings"
 	"github.com/oomph-ac/mv/multiversion/mv618/packet"
 	"github.com/oomph-ac/mv/multiversion/mv622"
+	v662packet "github.com/oomph-ac/mv/multiversion/mv662/packet"
 	"github.com/oomph-ac/mv/multiversion/util"
 	"github.com/sandertv/gophertunnel/minecraft"
 	"github.com/sandertv/gophertunnel/minecraft/protocol"
 	gtpacket "github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 )
 
+var capabilities = sync.OnceValue(func() capability.Set {
+	return capability.Derive(packet.NewServerPool(), packet.NewClientPool(), Mapping())
+})
+
 type Protocol struct{}
 
 func (Protocol) ID() int32 {
@@ -38,20 +47,21 @@ func (Protocol) Encryption(key [32]byte) gtpacket.Encryption {
 	return gtpacket.NewCTREncryption(key[:])
 }
 
-func (Protocol) ConvertToLatest(pk gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
-	if upgraded, ok := util.DefaultUpgrade(conn, pk, Mapping); ok {
-		if upgraded == nil {
-			return []gtpacket.Packet{}
-		}
+func (Protocol) Mapping() mappings.MVMapping {
+	return Mapping()
+}
 
-		return Upgrade([]gtpacket.Packet{upgraded}, conn)
-	}
+func (Protocol) Capabilities() capability.Set {
+	return capabilities()
+}
 
-	return Upgrade([]gtpacket.Packet{pk}, conn)
+func (Protocol) ConvertToLatest(pk gtpacket.Packet, conn *minecraft.Conn) (packets []gtpacket.Packet) {
+	defer util.RecoverMalformed(conn, pk, &packets)
+	return util.UpgradePacket(conn, pk, Mapping(), Upgrade)
 }
 
 func (Protocol) ConvertFromLatest(pk gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
-	if downgraded, ok := util.DefaultDowngrade(conn, pk, Mapping); ok {
+	if downgraded, ok := util.DefaultDowngrade(conn, pk, Mapping()); ok {
 		return Downgrade([]gtpacket.Packet{downgraded}, conn)
 	}
 
@@ -59,13 +69,29 @@ func (Protocol) ConvertFromLatest(pk gtpacket.Packet, conn *minecraft.Conn) []gt
 }
 
 func Upgrade(pks []gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
-	return mv622.Upgrade(pks, conn)
+	packets := make([]gtpacket.Packet, 0, len(pks))
+	for _, pk := range pks {
+		switch pk := pk.(type) {
+		case *packet.Disconnect:
+			packets = append(packets, &gtpacket.Disconnect{
+				HideDisconnectionScreen: pk.HideDisconnectionScreen,
+				Message:                 pk.Message,
+			})
+		default:
+			packets = append(packets, pk)
+		}
+	}
+
+	return mv622.Upgrade(packets, conn)
 }
 
 func Downgrade(pks []gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
 	packets := []gtpacket.Packet{}
 	for _, pk := range mv622.Downgrade(pks, conn) {
 		switch pk := pk.(type) {
+		case *v662packet.StartGame:
+			pk.Blocks = util.DowngradeBlockDefinitions(pk.Blocks, util.MolangVersion1_20_10)
+			packets = append(packets, pk)
 		case *gtpacket.Disconnect:
 			packets = append(packets, &packet.Disconnect{
 				HideDisconnectionScreen: pk.HideDisconnectionScreen,
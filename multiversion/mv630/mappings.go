@@ -2,6 +2,7 @@ package mv630
 
 import (
 	_ "embed"
+	"sync"
 
 	"github.com/oomph-ac/mv/multiversion/mappings"
 )
@@ -11,10 +12,15 @@ var (
 	blockStates []byte
 	//go:embed mappings/item_runtime_ids.nbt
 	itemRuntimeIDs []byte
-
-	Mapping mappings.MVMapping
 )
 
 func init() {
-	Mapping = mappings.Mapping(blockStates, itemRuntimeIDs, false)
+	mappings.Register(Protocol{}.ID(), blockStates)
 }
+
+// Mapping returns the block and item mappings of the protocol. They are loaded on first use and shared with
+// other protocols using the same block palette or item table. A block palette set using mappings.SetOverrides
+// replaces the embedded palette, while an item table set using it is layered on top of the embedded table.
+var Mapping = sync.OnceValue(func() mappings.MVMapping {
+	return mappings.Load(Protocol{}.ID(), blockStates, itemRuntimeIDs, false)
+})
@@ -115,3 +115,22 @@ func DowngradePlayerEntries(entries []protocol.PlayerListEntry) []PlayerListEntr
 
 	return new
 }
+
+func UpgradePlayerEntries(entries []PlayerListEntry) []protocol.PlayerListEntry {
+	new := make([]protocol.PlayerListEntry, 0, len(entries))
+	for _, e := range entries {
+		new = append(new, protocol.PlayerListEntry{
+			UUID:           e.UUID,
+			EntityUniqueID: e.EntityUniqueID,
+			Username:       e.Username,
+			XUID:           e.XUID,
+			PlatformChatID: e.PlatformChatID,
+			BuildPlatform:  e.BuildPlatform,
+			Skin:           e.Skin,
+			Teacher:        e.Teacher,
+			Host:           e.Host,
+		})
+	}
+
+	return new
+}
@@ -1,6 +1,10 @@
 package mv630
 
 import (
+	"sync"
+
+	"github.com/oomph-ac/mv/multiversion/capability"
+	"github.com/oomph-ac/mv/multiversion/mappings"
 	"github.com/oomph-ac/mv/multiversion/mv630/packet"
 	"github.com/oomph-ac/mv/multiversion/mv649"
 	"github.com/oomph-ac/mv/multiversion/util"
@@ -11,6 +15,10 @@ import (
 	gtpacket "github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 )
 
+var capabilities = sync.OnceValue(func() capability.Set {
+	return capability.Derive(packet.NewServerPool(), packet.NewClientPool(), Mapping())
+})
+
 type Protocol struct{}
 
 func (Protocol) ID() int32 {
@@ -40,20 +48,21 @@ func (Protocol) Encryption(key [32]byte) gtpacket.Encryption {
 	return gtpacket.NewCTREncryption(key[:])
 }
 
-func (Protocol) ConvertToLatest(pk gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
-	if upgraded, ok := util.DefaultUpgrade(conn, pk, Mapping); ok {
-		if upgraded == nil {
-			return []gtpacket.Packet{}
-		}
+func (Protocol) Mapping() mappings.MVMapping {
+	return Mapping()
+}
 
-		return Upgrade([]gtpacket.Packet{upgraded}, conn)
-	}
+func (Protocol) Capabilities() capability.Set {
+	return capabilities()
+}
 
-	return Upgrade([]gtpacket.Packet{pk}, conn)
+func (Protocol) ConvertToLatest(pk gtpacket.Packet, conn *minecraft.Conn) (packets []gtpacket.Packet) {
+	defer util.RecoverMalformed(conn, pk, &packets)
+	return util.UpgradePacket(conn, pk, Mapping(), Upgrade)
 }
 
 func (Protocol) ConvertFromLatest(pk gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
-	if downgraded, ok := util.DefaultDowngrade(conn, pk, Mapping); ok {
+	if downgraded, ok := util.DefaultDowngrade(conn, pk, Mapping()); ok {
 		return Downgrade([]gtpacket.Packet{downgraded}, conn)
 	}
 
@@ -64,6 +73,10 @@ func Upgrade(pks []gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
 	packets := make([]gtpacket.Packet, 0, len(pks))
 	for _, pk := range pks {
 		switch pk := pk.(type) {
+		case *gtpacket.ItemComponent:
+			packets = append(packets, &gtpacket.ItemComponent{
+				Items: util.TranslateItemComponents(pk.Items, util.UpgradeItemIcon),
+			})
 		case *packet.PlayerAuthInput:
 			packets = append(packets, &v649packet.PlayerAuthInput{
 				Pitch:                  pk.Pitch,
@@ -84,6 +97,21 @@ func Upgrade(pks []gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
 				AnalogueMoveVector:     pk.AnalogueMoveVector,
 				ClientPredictedVehicle: 0,
 			})
+		case *packet.LevelChunk:
+			packets = append(packets, &gtpacket.LevelChunk{
+				Position:        pk.Position,
+				Dimension:       conn.GameData().Dimension,
+				HighestSubChunk: pk.HighestSubChunk,
+				SubChunkCount:   pk.SubChunkCount,
+				CacheEnabled:    pk.CacheEnabled,
+				BlobHashes:      pk.BlobHashes,
+				RawPayload:      pk.RawPayload,
+			})
+		case *packet.PlayerList:
+			packets = append(packets, &gtpacket.PlayerList{
+				ActionType: pk.ActionType,
+				Entries:    packet.UpgradePlayerEntries(pk.Entries),
+			})
 		default:
 			packets = append(packets, pk)
 		}
@@ -96,6 +124,30 @@ func Downgrade(pks []gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
 	packets := make([]gtpacket.Packet, 0, len(pks))
 	for _, pk := range mv649.Downgrade(pks, conn) {
 		switch pk := pk.(type) {
+		case *gtpacket.ItemComponent:
+			// Only the icon of custom items is converted: Other components are sent as they are.
+			packets = append(packets, &gtpacket.ItemComponent{
+				Items: util.TranslateItemComponents(pk.Items, util.DowngradeItemIcon),
+			})
+		case *v649packet.PlayerAuthInput:
+			packets = append(packets, &packet.PlayerAuthInput{
+				Pitch:               pk.Pitch,
+				Yaw:                 pk.Yaw,
+				Position:            pk.Position,
+				MoveVector:          pk.MoveVector,
+				HeadYaw:             pk.HeadYaw,
+				InputData:           pk.InputData,
+				InputMode:           pk.InputMode,
+				PlayMode:            pk.PlayMode,
+				InteractionModel:    pk.InteractionModel,
+				GazeDirection:       pk.GazeDirection,
+				Tick:                pk.Tick,
+				Delta:               pk.Delta,
+				ItemInteractionData: pk.ItemInteractionData,
+				ItemStackRequest:    pk.ItemStackRequest,
+				BlockActions:        pk.BlockActions,
+				AnalogueMoveVector:  pk.AnalogueMoveVector,
+			})
 		case *gtpacket.LevelChunk:
 			packets = append(packets, &packet.LevelChunk{
 				Position:        pk.Position,
@@ -107,7 +159,8 @@ func Downgrade(pks []gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
 			})
 		case *gtpacket.PlayerList:
 			packets = append(packets, &packet.PlayerList{
-				Entries: packet.DowngradePlayerEntries(pk.Entries),
+				ActionType: pk.ActionType,
+				Entries:    packet.DowngradePlayerEntries(pk.Entries),
 			})
 		default:
 			packets = append(packets, pk)
@@ -0,0 +1,51 @@
+package mv630
+
+import (
+	"testing"
+
+	"github.com/oomph-ac/mv/multiversion/internal/conformance"
+	"github.com/oomph-ac/mv/multiversion/util"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	gtpacket "github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// TestConformance round-trips every packet overridden by the protocol through a downgrade and upgrade.
+func TestConformance(t *testing.T) {
+	conformance.Test(t, Protocol{})
+}
+
+// FuzzConvertToLatest fuzzes the conversion of packets read from a connection to the latest version.
+func FuzzConvertToLatest(f *testing.F) {
+	conformance.Fuzz(f, Protocol{}, func(pk gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
+		return util.UpgradePacket(conn, pk, Mapping(), Upgrade)
+	})
+}
+
+// TestItemComponent tests that the icons of custom items are converted in a new ItemComponent packet, leaving
+// the packet passed as it is.
+func TestItemComponent(t *testing.T) {
+	pk := &gtpacket.ItemComponent{Items: []protocol.ItemComponentEntry{{Name: "test:item", Data: map[string]any{
+		"components": map[string]any{"item_properties": map[string]any{
+			"minecraft:icon": map[string]any{"textures": map[string]any{"default": "item"}},
+		}},
+	}}}}
+	items := pk.Items
+
+	downgraded := Downgrade([]gtpacket.Packet{pk}, &minecraft.Conn{})
+	if len(downgraded) != 1 || downgraded[0] == pk {
+		t.Fatalf("expected a new ItemComponent packet, got %v", downgraded)
+	}
+	if &pk.Items[0] != &items[0] {
+		t.Errorf("the items of the packet passed were replaced")
+	}
+	icon := downgraded[0].(*gtpacket.ItemComponent).Items[0].Data["components"].(map[string]any)["item_properties"].(map[string]any)["minecraft:icon"]
+	if texture := icon.(map[string]any)["texture"]; texture != "item" {
+		t.Errorf("expected the icon to have texture %q, got %v", "item", texture)
+	}
+
+	upgraded := Upgrade(downgraded, &minecraft.Conn{})
+	if len(upgraded) != 1 || upgraded[0] == downgraded[0] {
+		t.Fatalf("expected a new ItemComponent packet, got %v", upgraded)
+	}
+}
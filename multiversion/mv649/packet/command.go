@@ -1,5 +1,7 @@
 package packet
 
+import "github.com/sandertv/gophertunnel/minecraft/protocol"
+
 const (
 	CommandArgTypeEquipmentSlots = 43
 	CommandArgTypeString         = 44
@@ -11,3 +13,27 @@ const (
 	CommandArgTypeBlockStates    = 71
 	CommandArgTypeCommand        = 74
 )
+
+// CommandArgTypes maps every command argument type of the latest version to the argument type of 1.20.60.
+// Argument types below 43 are the same in both versions.
+var CommandArgTypes = map[uint32]uint32{
+	protocol.CommandArgTypeInt:             protocol.CommandArgTypeInt,
+	protocol.CommandArgTypeFloat:           protocol.CommandArgTypeFloat,
+	protocol.CommandArgTypeValue:           protocol.CommandArgTypeValue,
+	protocol.CommandArgTypeWildcardInt:     protocol.CommandArgTypeWildcardInt,
+	protocol.CommandArgTypeOperator:        protocol.CommandArgTypeOperator,
+	protocol.CommandArgTypeCompareOperator: protocol.CommandArgTypeCompareOperator,
+	protocol.CommandArgTypeTarget:          protocol.CommandArgTypeTarget,
+	protocol.CommandArgTypeWildcardTarget:  protocol.CommandArgTypeWildcardTarget,
+	protocol.CommandArgTypeFilepath:        protocol.CommandArgTypeFilepath,
+	protocol.CommandArgTypeIntegerRange:    protocol.CommandArgTypeIntegerRange,
+	protocol.CommandArgTypeEquipmentSlots:  CommandArgTypeEquipmentSlots,
+	protocol.CommandArgTypeString:          CommandArgTypeString,
+	protocol.CommandArgTypeBlockPosition:   CommandArgTypeBlockPosition,
+	protocol.CommandArgTypePosition:        CommandArgTypePosition,
+	protocol.CommandArgTypeMessage:         CommandArgTypeMessage,
+	protocol.CommandArgTypeRawText:         CommandArgTypeRawText,
+	protocol.CommandArgTypeJSON:            CommandArgTypeJSON,
+	protocol.CommandArgTypeBlockStates:     CommandArgTypeBlockStates,
+	protocol.CommandArgTypeCommand:         CommandArgTypeCommand,
+}
@@ -1,10 +1,14 @@
 package mv649
 
 import (
+	"sync"
+
 	"github.com/go-gl/mathgl/mgl32"
 	"github.com/sandertv/gophertunnel/minecraft"
 	"github.com/sandertv/gophertunnel/minecraft/protocol"
 
+	"github.com/oomph-ac/mv/multiversion/capability"
+	"github.com/oomph-ac/mv/multiversion/mappings"
 	"github.com/oomph-ac/mv/multiversion/mv649/packet"
 	"github.com/oomph-ac/mv/multiversion/mv662"
 	v662packet "github.com/oomph-ac/mv/multiversion/mv662/packet"
@@ -12,6 +16,17 @@ import (
 	gtpacket "github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 )
 
+var capabilities = sync.OnceValue(func() capability.Set {
+	return capability.Derive(packet.NewServerPool(), packet.NewClientPool(), Mapping())
+})
+
+var (
+	// commandArgTypes translates the command argument types of the latest version to those of 1.20.60, and
+	// legacyCommandArgTypes translates them back.
+	commandArgTypes       = util.CommandArgTypes(packet.CommandArgTypes)
+	legacyCommandArgTypes = commandArgTypes.Inverse()
+)
+
 type Protocol struct{}
 
 func (Protocol) ID() int32 {
@@ -41,20 +56,21 @@ func (Protocol) Encryption(key [32]byte) gtpacket.Encryption {
 	return gtpacket.NewCTREncryption(key[:])
 }
 
-func (Protocol) ConvertToLatest(pk gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
-	if upgraded, ok := util.DefaultUpgrade(conn, pk, Mapping); ok {
-		if upgraded == nil {
-			return []gtpacket.Packet{}
-		}
+func (Protocol) Mapping() mappings.MVMapping {
+	return Mapping()
+}
 
-		return Upgrade([]gtpacket.Packet{upgraded}, conn)
-	}
+func (Protocol) Capabilities() capability.Set {
+	return capabilities()
+}
 
-	return Upgrade([]gtpacket.Packet{pk}, conn)
+func (Protocol) ConvertToLatest(pk gtpacket.Packet, conn *minecraft.Conn) (packets []gtpacket.Packet) {
+	defer util.RecoverMalformed(conn, pk, &packets)
+	return util.UpgradePacket(conn, pk, Mapping(), Upgrade)
 }
 
 func (Protocol) ConvertFromLatest(pk gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
-	if downgraded, ok := util.DefaultDowngrade(conn, pk, Mapping); ok {
+	if downgraded, ok := util.DefaultDowngrade(conn, pk, Mapping()); ok {
 		return Downgrade([]gtpacket.Packet{downgraded}, conn)
 	}
 
@@ -92,6 +108,32 @@ func Upgrade(pks []gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
 				PageCount: pk.PageCount,
 				Position:  pk.Position,
 			})
+		case *packet.SetActorMotion:
+			packets = append(packets, &gtpacket.SetActorMotion{
+				EntityRuntimeID: pk.EntityRuntimeID,
+				Velocity:        pk.Velocity,
+			})
+		case *packet.ResourcePacksInfo:
+			packets = append(packets, &gtpacket.ResourcePacksInfo{
+				TexturePackRequired: pk.TexturePackRequired,
+				HasScripts:          pk.HasScripts,
+				BehaviourPacks:      pk.BehaviourPacks,
+				TexturePacks:        pk.TexturePacks,
+				ForcingServerPacks:  pk.ForcingServerPacks,
+				PackURLs:            pk.PackURLs,
+			})
+		case *packet.MobEffect:
+			packets = append(packets, &gtpacket.MobEffect{
+				EntityRuntimeID: pk.EntityRuntimeID,
+				Operation:       pk.Operation,
+				EffectType:      pk.EffectType,
+				Amplifier:       pk.Amplifier,
+				Particles:       pk.Particles,
+				Duration:        pk.Duration,
+			})
+		case *gtpacket.AvailableCommands:
+			pk.Commands = legacyCommandArgTypes.Commands(pk.Commands)
+			packets = append(packets, pk)
 		default:
 			packets = append(packets, pk)
 		}
@@ -106,64 +148,34 @@ func Downgrade(pks []gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
 
 	for _, pk := range downgraded {
 		switch pk := pk.(type) {
+		case *v662packet.PlayerAuthInput:
+			packets = append(packets, &packet.PlayerAuthInput{
+				Pitch:                  pk.Pitch,
+				Yaw:                    pk.Yaw,
+				Position:               pk.Position,
+				MoveVector:             pk.MoveVector,
+				HeadYaw:                pk.HeadYaw,
+				InputData:              pk.InputData,
+				InputMode:              pk.InputMode,
+				PlayMode:               pk.PlayMode,
+				InteractionModel:       pk.InteractionModel,
+				GazeDirection:          pk.GazeDirection,
+				Tick:                   pk.Tick,
+				Delta:                  pk.Delta,
+				ItemInteractionData:    pk.ItemInteractionData,
+				ItemStackRequest:       pk.ItemStackRequest,
+				BlockActions:           pk.BlockActions,
+				ClientPredictedVehicle: pk.ClientPredictedVehicle,
+				AnalogueMoveVector:     pk.AnalogueMoveVector,
+			})
+		case *gtpacket.LecternUpdate:
+			packets = append(packets, &packet.LecternUpdate{
+				Page:      pk.Page,
+				PageCount: pk.PageCount,
+				Position:  pk.Position,
+			})
 		case *gtpacket.AvailableCommands:
-			// HACK!!! Why??!?!?! because GOLANG doesn't like it when i just replace p.Type :////
-			cmds := make([]protocol.Command, 0, len(pk.Commands))
-			for _, c := range pk.Commands {
-				cmd := protocol.Command{}
-				cmd.Name = c.Name
-				cmd.Description = c.Description
-				cmd.Flags = c.Flags
-				cmd.PermissionLevel = c.PermissionLevel
-				cmd.AliasesOffset = c.AliasesOffset
-				cmd.ChainedSubcommandOffsets = c.ChainedSubcommandOffsets
-				cmd.Overloads = make([]protocol.CommandOverload, 0, len(c.Overloads))
-
-				for _, o := range c.Overloads {
-					overload := protocol.CommandOverload{}
-					overload.Chaining = o.Chaining
-					overload.Parameters = make([]protocol.CommandParameter, 0, len(o.Parameters))
-
-					for _, p := range o.Parameters {
-						param := protocol.CommandParameter{}
-						param.Name = p.Name
-						param.Optional = p.Optional
-						param.Options = p.Options
-
-						var newT uint32 = protocol.CommandArgValid
-						if p.Type == (protocol.CommandArgTypeEquipmentSlots | protocol.CommandArgValid) {
-							newT |= packet.CommandArgTypeEquipmentSlots
-						} else if p.Type == (protocol.CommandArgTypeString | protocol.CommandArgValid) {
-							newT |= packet.CommandArgTypeString
-						} else if p.Type == (protocol.CommandArgTypeBlockPosition | protocol.CommandArgValid) {
-							newT |= packet.CommandArgTypeBlockPosition
-						} else if p.Type == (protocol.CommandArgTypePosition | protocol.CommandArgValid) {
-							newT |= packet.CommandArgTypePosition
-						} else if p.Type == (protocol.CommandArgTypeMessage | protocol.CommandArgValid) {
-							newT |= packet.CommandArgTypeMessage
-						} else if p.Type == (protocol.CommandArgTypeRawText | protocol.CommandArgValid) {
-							newT |= packet.CommandArgTypeRawText
-						} else if p.Type == (protocol.CommandArgTypeJSON | protocol.CommandArgValid) {
-							newT |= packet.CommandArgTypeJSON
-						} else if p.Type == (protocol.CommandArgTypeBlockStates | protocol.CommandArgValid) {
-							newT |= packet.CommandArgTypeBlockStates
-						} else if p.Type == (protocol.CommandArgTypeCommand | protocol.CommandArgValid) {
-							newT |= packet.CommandArgTypeCommand
-						} else {
-							// We don't need to downgrade these.
-							continue
-						}
-
-						param.Type = newT
-						overload.Parameters = append(overload.Parameters, param)
-					}
-
-					cmd.Overloads = append(cmd.Overloads, overload)
-				}
-
-				cmds = append(cmds, cmd)
-			}
-			pk.Commands = cmds
+			pk.Commands = commandArgTypes.Commands(pk.Commands)
 			packets = append(packets, pk)
 		case *gtpacket.SetActorMotion:
 			packets = append(packets, &packet.SetActorMotion{
@@ -0,0 +1,62 @@
+package mv649
+
+import (
+	"go/constant"
+	"go/importer"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/oomph-ac/mv/multiversion/internal/conformance"
+	v575packet "github.com/oomph-ac/mv/multiversion/mv575/packet"
+	v589packet "github.com/oomph-ac/mv/multiversion/mv589/packet"
+	"github.com/oomph-ac/mv/multiversion/mv649/packet"
+	"github.com/oomph-ac/mv/multiversion/util"
+	"github.com/sandertv/gophertunnel/minecraft"
+	gtpacket "github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// TestConformance round-trips every packet overridden by the protocol through a downgrade and upgrade.
+func TestConformance(t *testing.T) {
+	conformance.Test(t, Protocol{})
+}
+
+// FuzzConvertToLatest fuzzes the conversion of packets read from a connection to the latest version.
+func FuzzConvertToLatest(f *testing.F) {
+	conformance.Fuzz(f, Protocol{}, func(pk gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
+		return util.UpgradePacket(conn, pk, Mapping(), Upgrade)
+	})
+}
+
+// TestCommandArgTypes tests that every command argument type declared by the latest version has a mapping to
+// 1.20.60, and that each of those has a mapping to 1.20.0 and 1.19.70 in turn.
+func TestCommandArgTypes(t *testing.T) {
+	pkg, err := importer.ForCompiler(token.NewFileSet(), "source", nil).Import("github.com/sandertv/gophertunnel/minecraft/protocol")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var n int
+	for _, name := range pkg.Scope().Names() {
+		c, ok := pkg.Scope().Lookup(name).(*types.Const)
+		if !ok || !strings.HasPrefix(name, "CommandArgType") {
+			continue
+		}
+		n++
+		v, _ := constant.Uint64Val(c.Val())
+		translated, ok := packet.CommandArgTypes[uint32(v)]
+		if !ok {
+			t.Errorf("protocol.%v (%v) has no mapping to 1.20.60", name, v)
+			continue
+		}
+		if _, ok := v589packet.CommandArgTypes[translated]; !ok {
+			t.Errorf("protocol.%v (%v in 1.20.60) has no mapping to 1.20.0", name, translated)
+		}
+		if _, ok := v575packet.CommandArgTypes[translated]; !ok {
+			t.Errorf("protocol.%v (%v in 1.20.60) has no mapping to 1.19.70", name, translated)
+		}
+	}
+	if n != len(packet.CommandArgTypes) {
+		t.Errorf("expected %v command argument types, got %v mappings", n, len(packet.CommandArgTypes))
+	}
+}
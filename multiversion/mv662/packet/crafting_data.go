@@ -33,14 +33,24 @@ func (*CraftingData) ID() uint32 {
 }
 
 func (pk *CraftingData) Marshal(io protocol.IO) {
-	w := io.(*protocol.Writer)
 	protocol.FuncSlice(io, &pk.Recipes, func(x *protocol.Recipe) {
-		var recipeType int32
-		if !lookupRecipeType(*x, &recipeType) {
-			w.UnknownEnumOption(fmt.Sprintf("%T", *x), "crafting recipe type")
+		switch io := io.(type) {
+		case *protocol.Reader:
+			var recipeType int32
+			io.Varint32(&recipeType)
+			if !lookupRecipe(recipeType, x) {
+				io.UnknownEnumOption(recipeType, "crafting data recipe type")
+				return
+			}
+			(*x).Unmarshal(io)
+		case *protocol.Writer:
+			var recipeType int32
+			if !lookupRecipeType(*x, &recipeType) {
+				io.UnknownEnumOption(fmt.Sprintf("%T", *x), "crafting recipe type")
+			}
+			io.Varint32(&recipeType)
+			(*x).Marshal(io)
 		}
-		w.Varint32(&recipeType)
-		(*x).Marshal(w)
 	})
 	protocol.Slice(io, &pk.PotionRecipes)
 	protocol.Slice(io, &pk.PotionContainerChangeRecipes)
@@ -48,6 +58,36 @@ func (pk *CraftingData) Marshal(io protocol.IO) {
 	io.Bool(&pk.ClearRecipes)
 }
 
+// lookupRecipe looks up the Recipe for a recipe type. False is returned if not
+// found.
+func lookupRecipe(recipeType int32, x *protocol.Recipe) bool {
+	switch recipeType {
+	case protocol.RecipeShapeless:
+		*x = &protocol.ShapelessRecipe{}
+	case protocol.RecipeShaped:
+		*x = &ShapedRecipe{}
+	case protocol.RecipeFurnace:
+		*x = &protocol.FurnaceRecipe{}
+	case protocol.RecipeFurnaceData:
+		*x = &protocol.FurnaceDataRecipe{}
+	case protocol.RecipeMulti:
+		*x = &protocol.MultiRecipe{}
+	case protocol.RecipeShulkerBox:
+		*x = &protocol.ShulkerBoxRecipe{}
+	case protocol.RecipeShapelessChemistry:
+		*x = &protocol.ShapelessChemistryRecipe{}
+	case protocol.RecipeShapedChemistry:
+		*x = &ShapedChemistryRecipe{}
+	case protocol.RecipeSmithingTransform:
+		*x = &protocol.SmithingTransformRecipe{}
+	case protocol.RecipeSmithingTrim:
+		*x = &protocol.SmithingTrimRecipe{}
+	default:
+		return false
+	}
+	return true
+}
+
 // lookupRecipeType looks up the recipe type for a Recipe. False is returned if
 // none was found.
 func lookupRecipeType(x protocol.Recipe, recipeType *int32) bool {
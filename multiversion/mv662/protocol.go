@@ -1,6 +1,10 @@
 package mv662
 
 import (
+	"sync"
+
+	"github.com/oomph-ac/mv/multiversion/capability"
+	"github.com/oomph-ac/mv/multiversion/mappings"
 	"github.com/oomph-ac/mv/multiversion/mv662/packet"
 	"github.com/oomph-ac/mv/multiversion/util"
 	"github.com/sandertv/gophertunnel/minecraft"
@@ -8,6 +12,10 @@ import (
 	gtpacket "github.com/sandertv/gophertunnel/minecraft/protocol/packet"
 )
 
+var capabilities = sync.OnceValue(func() capability.Set {
+	return capability.Derive(packet.NewServerPool(), packet.NewClientPool(), Mapping())
+})
+
 type Protocol struct{}
 
 func (Protocol) ID() int32 {
@@ -37,20 +45,21 @@ func (Protocol) Encryption(key [32]byte) gtpacket.Encryption {
 	return gtpacket.NewCTREncryption(key[:])
 }
 
-func (Protocol) ConvertToLatest(pk gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
-	if upgraded, ok := util.DefaultUpgrade(conn, pk, Mapping); ok {
-		if upgraded == nil {
-			return []gtpacket.Packet{}
-		}
+func (Protocol) Mapping() mappings.MVMapping {
+	return Mapping()
+}
 
-		return Upgrade([]gtpacket.Packet{upgraded}, conn)
-	}
+func (Protocol) Capabilities() capability.Set {
+	return capabilities()
+}
 
-	return Upgrade([]gtpacket.Packet{pk}, conn)
+func (Protocol) ConvertToLatest(pk gtpacket.Packet, conn *minecraft.Conn) (packets []gtpacket.Packet) {
+	defer util.RecoverMalformed(conn, pk, &packets)
+	return util.UpgradePacket(conn, pk, Mapping(), Upgrade)
 }
 
 func (Protocol) ConvertFromLatest(pk gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
-	if downgraded, ok := util.DefaultDowngrade(conn, pk, Mapping); ok {
+	if downgraded, ok := util.DefaultDowngrade(conn, pk, Mapping()); ok {
 		return Downgrade([]gtpacket.Packet{downgraded}, conn)
 	}
 
@@ -82,6 +91,159 @@ func Upgrade(pks []gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
 				ClientPredictedVehicle: pk.ClientPredictedVehicle,
 				AnalogueMoveVector:     pk.AnalogueMoveVector,
 			})
+		case *packet.ResourcePackStack:
+			packets = append(packets, &gtpacket.ResourcePackStack{
+				TexturePackRequired:          pk.TexturePackRequired,
+				BehaviourPacks:               pk.BehaviourPacks,
+				TexturePacks:                 pk.TexturePacks,
+				BaseGameVersion:              pk.BaseGameVersion,
+				Experiments:                  pk.Experiments,
+				ExperimentsPreviouslyToggled: pk.ExperimentsPreviouslyToggled,
+			})
+		case *packet.StartGame:
+			packets = append(packets, &gtpacket.StartGame{
+				EntityUniqueID:                 pk.EntityUniqueID,
+				EntityRuntimeID:                pk.EntityRuntimeID,
+				PlayerGameMode:                 pk.PlayerGameMode,
+				PlayerPosition:                 pk.PlayerPosition,
+				Pitch:                          pk.Pitch,
+				Yaw:                            pk.Yaw,
+				WorldSeed:                      pk.WorldSeed,
+				SpawnBiomeType:                 pk.SpawnBiomeType,
+				UserDefinedBiomeName:           pk.UserDefinedBiomeName,
+				Dimension:                      pk.Dimension,
+				Generator:                      pk.Generator,
+				WorldGameMode:                  pk.WorldGameMode,
+				Difficulty:                     pk.Difficulty,
+				WorldSpawn:                     pk.WorldSpawn,
+				AchievementsDisabled:           pk.AchievementsDisabled,
+				EditorWorldType:                pk.EditorWorldType,
+				CreatedInEditor:                pk.CreatedInEditor,
+				ExportedFromEditor:             pk.ExportedFromEditor,
+				DayCycleLockTime:               pk.DayCycleLockTime,
+				EducationEditionOffer:          pk.EducationEditionOffer,
+				EducationFeaturesEnabled:       pk.EducationFeaturesEnabled,
+				EducationProductID:             pk.EducationProductID,
+				RainLevel:                      pk.RainLevel,
+				LightningLevel:                 pk.LightningLevel,
+				ConfirmedPlatformLockedContent: pk.ConfirmedPlatformLockedContent,
+				MultiPlayerGame:                pk.MultiPlayerGame,
+				LANBroadcastEnabled:            pk.LANBroadcastEnabled,
+				XBLBroadcastMode:               pk.XBLBroadcastMode,
+				PlatformBroadcastMode:          pk.PlatformBroadcastMode,
+				CommandsEnabled:                pk.CommandsEnabled,
+				TexturePackRequired:            pk.TexturePackRequired,
+				GameRules:                      pk.GameRules,
+				Experiments:                    pk.Experiments,
+				ExperimentsPreviouslyToggled:   pk.ExperimentsPreviouslyToggled,
+				BonusChestEnabled:              pk.BonusChestEnabled,
+				StartWithMapEnabled:            pk.StartWithMapEnabled,
+				PlayerPermissions:              pk.PlayerPermissions,
+				ServerChunkTickRadius:          pk.ServerChunkTickRadius,
+				HasLockedBehaviourPack:         pk.HasLockedBehaviourPack,
+				HasLockedTexturePack:           pk.HasLockedTexturePack,
+				FromLockedWorldTemplate:        pk.FromLockedWorldTemplate,
+				MSAGamerTagsOnly:               pk.MSAGamerTagsOnly,
+				FromWorldTemplate:              pk.FromWorldTemplate,
+				WorldTemplateSettingsLocked:    pk.WorldTemplateSettingsLocked,
+				OnlySpawnV1Villagers:           pk.OnlySpawnV1Villagers,
+				PersonaDisabled:                pk.PersonaDisabled,
+				CustomSkinsDisabled:            pk.CustomSkinsDisabled,
+				EmoteChatMuted:                 pk.EmoteChatMuted,
+				BaseGameVersion:                pk.BaseGameVersion,
+				LimitedWorldWidth:              pk.LimitedWorldWidth,
+				LimitedWorldDepth:              pk.LimitedWorldDepth,
+				NewNether:                      pk.NewNether,
+				EducationSharedResourceURI:     pk.EducationSharedResourceURI,
+				ForceExperimentalGameplay:      pk.ForceExperimentalGameplay,
+				LevelID:                        pk.LevelID,
+				WorldName:                      pk.WorldName,
+				TemplateContentIdentity:        pk.TemplateContentIdentity,
+				Trial:                          pk.Trial,
+				PlayerMovementSettings:         pk.PlayerMovementSettings,
+				Time:                           pk.Time,
+				EnchantmentSeed:                pk.EnchantmentSeed,
+				Blocks:                         pk.Blocks,
+				Items:                          pk.Items,
+				MultiPlayerCorrelationID:       pk.MultiPlayerCorrelationID,
+				ServerAuthoritativeInventory:   pk.ServerAuthoritativeInventory,
+				GameVersion:                    pk.GameVersion,
+				PropertyData:                   pk.PropertyData,
+				ServerBlockStateChecksum:       pk.ServerBlockStateChecksum,
+				ClientSideGeneration:           pk.ClientSideGeneration,
+				WorldTemplateID:                pk.WorldTemplateID,
+				ChatRestrictionLevel:           pk.ChatRestrictionLevel,
+				DisablePlayerInteractions:      pk.DisablePlayerInteractions,
+				UseBlockNetworkIDHashes:        pk.UseBlockNetworkIDHashes,
+				ServerAuthoritativeSound:       pk.ServerAuthoritativeSound,
+			})
+		case *packet.UpdateBlockSynced:
+			packets = append(packets, &gtpacket.UpdateBlockSynced{
+				Position:          pk.Position,
+				NewBlockRuntimeID: pk.NewBlockRuntimeID,
+				Flags:             pk.Flags,
+				Layer:             pk.Layer,
+				EntityUniqueID:    uint64(pk.EntityUniqueID),
+				TransitionType:    pk.TransitionType,
+			})
+		case *packet.UpdatePlayerGameType:
+			packets = append(packets, &gtpacket.UpdatePlayerGameType{
+				GameType:       pk.GameType,
+				PlayerUniqueID: pk.PlayerUniqueID,
+			})
+		case *packet.ClientBoundDebugRenderer:
+			packets = append(packets, &gtpacket.ClientBoundDebugRenderer{
+				Type:     pk.Type,
+				Text:     pk.Text,
+				Position: pk.Position,
+				Red:      pk.Red,
+				Green:    pk.Green,
+				Blue:     pk.Blue,
+				Alpha:    pk.Alpha,
+				Duration: pk.Duration,
+			})
+		case *packet.CraftingData:
+			recipes := make([]protocol.Recipe, 0, len(pk.Recipes))
+			for _, r := range pk.Recipes {
+				switch r := r.(type) {
+				case *packet.ShapedRecipe:
+					recipes = append(recipes, &protocol.ShapedRecipe{
+						RecipeID:        r.RecipeID,
+						Width:           r.Width,
+						Height:          r.Height,
+						Input:           r.Input,
+						Output:          r.Output,
+						UUID:            r.UUID,
+						Block:           r.Block,
+						Priority:        r.Priority,
+						RecipeNetworkID: r.RecipeNetworkID,
+					})
+				case *packet.ShapedChemistryRecipe:
+					recipes = append(recipes, &protocol.ShapedChemistryRecipe{
+						ShapedRecipe: protocol.ShapedRecipe{
+							RecipeID:        r.RecipeID,
+							Width:           r.Width,
+							Height:          r.Height,
+							Input:           r.Input,
+							Output:          r.Output,
+							UUID:            r.UUID,
+							Block:           r.Block,
+							Priority:        r.Priority,
+							RecipeNetworkID: r.RecipeNetworkID,
+						},
+					})
+				default:
+					recipes = append(recipes, r)
+				}
+			}
+
+			packets = append(packets, &gtpacket.CraftingData{
+				Recipes:                      recipes,
+				PotionRecipes:                pk.PotionRecipes,
+				PotionContainerChangeRecipes: pk.PotionContainerChangeRecipes,
+				MaterialReducers:             pk.MaterialReducers,
+				ClearRecipes:                 pk.ClearRecipes,
+			})
 		default:
 			packets = append(packets, pk)
 		}
@@ -95,6 +257,27 @@ func Downgrade(pks []gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
 
 	for _, pk := range pks {
 		switch pk := pk.(type) {
+		case *gtpacket.PlayerAuthInput:
+			packets = append(packets, &packet.PlayerAuthInput{
+				Pitch:                  pk.Pitch,
+				Yaw:                    pk.Yaw,
+				Position:               pk.Position,
+				MoveVector:             pk.MoveVector,
+				HeadYaw:                pk.HeadYaw,
+				InputData:              pk.InputData,
+				InputMode:              pk.InputMode,
+				PlayMode:               pk.PlayMode,
+				InteractionModel:       int32(pk.InteractionModel),
+				GazeDirection:          pk.GazeDirection,
+				Tick:                   pk.Tick,
+				Delta:                  pk.Delta,
+				ItemInteractionData:    pk.ItemInteractionData,
+				ItemStackRequest:       pk.ItemStackRequest,
+				BlockActions:           pk.BlockActions,
+				VehicleRotation:        pk.VehicleRotation,
+				ClientPredictedVehicle: pk.ClientPredictedVehicle,
+				AnalogueMoveVector:     pk.AnalogueMoveVector,
+			})
 		case *gtpacket.ResourcePackStack:
 			packets = append(packets, &packet.ResourcePackStack{
 				TexturePackRequired:          pk.TexturePackRequired,
@@ -159,6 +342,7 @@ func Downgrade(pks []gtpacket.Packet, conn *minecraft.Conn) []gtpacket.Packet {
 				LimitedWorldDepth:              pk.LimitedWorldDepth,
 				NewNether:                      pk.NewNether,
 				EducationSharedResourceURI:     pk.EducationSharedResourceURI,
+				ForceExperimentalGameplay:      pk.ForceExperimentalGameplay,
 				LevelID:                        pk.LevelID,
 				WorldName:                      pk.WorldName,
 				TemplateContentIdentity:        pk.TemplateContentIdentity,
@@ -0,0 +1,96 @@
+package util
+
+import (
+	"strings"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+const (
+	// MolangVersion1_19_60 is the latest version of Molang expressions supported by 1.19.60 up to 1.19.80.
+	MolangVersion1_19_60 int32 = 8
+	// MolangVersion1_20_0 is the latest version of Molang expressions supported by 1.20.0. It renamed
+	// query.block_property to query.block_state.
+	MolangVersion1_20_0 int32 = 9
+	// MolangVersion1_20_10 is the latest version of Molang expressions supported by 1.20.10 up to 1.20.30.
+	MolangVersion1_20_10 int32 = 10
+)
+
+// DowngradeBlockDefinitions returns the custom block definitions passed, as found in the StartGame packet,
+// converted for a version that supports Molang expressions up to the version passed. The molangVersion of the
+// definitions is lowered if needed and block state queries in the conditions of their permutations are renamed
+// for versions before 1.20.0. The definitions passed are not modified.
+//
+// Only the Molang version and the conditions of permutations are converted. Components, such as
+// minecraft:geometry and minecraft:material_instances, are copied as they are, so servers sending custom blocks
+// to older versions must only use component fields that those versions support.
+func DowngradeBlockDefinitions(entries []protocol.BlockEntry, molangVersion int32) []protocol.BlockEntry {
+	if len(entries) == 0 {
+		return entries
+	}
+	downgraded := make([]protocol.BlockEntry, len(entries))
+	for i, entry := range entries {
+		properties := cloneNBT(entry.Properties).(map[string]any)
+		if v, ok := properties["molangVersion"].(int32); ok && v > molangVersion {
+			properties["molangVersion"] = molangVersion
+		}
+		if molangVersion < MolangVersion1_20_0 {
+			forEachPermutation(properties, func(permutation map[string]any) {
+				if condition, ok := permutation["condition"].(string); ok {
+					permutation["condition"] = blockPropertyQueries.Replace(condition)
+				}
+			})
+		}
+		downgraded[i] = protocol.BlockEntry{Name: entry.Name, Properties: properties}
+	}
+	return downgraded
+}
+
+// blockPropertyQueries renames block state queries to the names they had before 1.20.0.
+var blockPropertyQueries = strings.NewReplacer(
+	"query.block_state(", "query.block_property(",
+	"q.block_state(", "q.block_property(",
+)
+
+// forEachPermutation calls f for every permutation of the custom block definition passed. Permutations are a
+// []map[string]any when set by a server, but a []any after being decoded.
+func forEachPermutation(properties map[string]any, f func(permutation map[string]any)) {
+	switch permutations := properties["permutations"].(type) {
+	case []map[string]any:
+		for _, permutation := range permutations {
+			f(permutation)
+		}
+	case []any:
+		for _, permutation := range permutations {
+			if permutation, ok := permutation.(map[string]any); ok {
+				f(permutation)
+			}
+		}
+	}
+}
+
+// cloneNBT returns a deep copy of the NBT value passed, so that it may be modified without modifying the value
+// passed.
+func cloneNBT(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k, val := range v {
+			m[k] = cloneNBT(val)
+		}
+		return m
+	case []map[string]any:
+		s := make([]map[string]any, len(v))
+		for i, val := range v {
+			s[i] = cloneNBT(val).(map[string]any)
+		}
+		return s
+	case []any:
+		s := make([]any, len(v))
+		for i, val := range v {
+			s[i] = cloneNBT(val)
+		}
+		return s
+	}
+	return v
+}
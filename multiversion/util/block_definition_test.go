@@ -0,0 +1,79 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// TestDowngradeBlockDefinitions tests that custom block definitions are converted for older versions without
+// modifying the definitions passed.
+func TestDowngradeBlockDefinitions(t *testing.T) {
+	entries := []protocol.BlockEntry{{Name: "test:block", Properties: map[string]any{
+		"molangVersion": int32(10),
+		"permutations": []map[string]any{{
+			"condition":  "query.block_state('test:open') == 1 && q.block_state('test:lit') == 0",
+			"components": map[string]any{},
+		}},
+	}}}
+
+	downgraded := DowngradeBlockDefinitions(entries, MolangVersion1_19_60)
+	if v := downgraded[0].Properties["molangVersion"]; v != MolangVersion1_19_60 {
+		t.Errorf("expected molang version %v, got %v", MolangVersion1_19_60, v)
+	}
+	want := "query.block_property('test:open') == 1 && q.block_property('test:lit') == 0"
+	if got := downgraded[0].Properties["permutations"].([]map[string]any)[0]["condition"]; got != want {
+		t.Errorf("expected condition %q, got %q", want, got)
+	}
+	if got := entries[0].Properties["permutations"].([]map[string]any)[0]["condition"]; got == want {
+		t.Errorf("the definitions passed were modified")
+	}
+
+	downgraded = DowngradeBlockDefinitions(entries, MolangVersion1_20_0)
+	if got := downgraded[0].Properties["permutations"].([]map[string]any)[0]["condition"]; got == want {
+		t.Errorf("expected block state queries to be kept for 1.20.0")
+	}
+}
+
+// TestDowngradeBlockDefinitionComponents tests that the components of custom blocks and their permutations are
+// copied as they are, including permutations that were decoded as a []any.
+func TestDowngradeBlockDefinitionComponents(t *testing.T) {
+	components := func() map[string]any {
+		return map[string]any{
+			"minecraft:geometry": map[string]any{
+				"identifier":      "geometry.test",
+				"bone_visibility": map[string]any{"lid": uint8(1)},
+			},
+			"minecraft:material_instances": map[string]any{
+				"mappings":  map[string]any{},
+				"materials": map[string]any{"*": map[string]any{"texture": "test", "render_method": "opaque"}},
+			},
+		}
+	}
+	entries := []protocol.BlockEntry{{Name: "test:block", Properties: map[string]any{
+		"molangVersion": int32(10),
+		"components":    components(),
+		"permutations": []any{map[string]any{
+			"condition":  "query.block_state('test:open') == 1",
+			"components": components(),
+		}},
+	}}}
+
+	downgraded := DowngradeBlockDefinitions(entries, MolangVersion1_19_60)
+	permutation := downgraded[0].Properties["permutations"].([]any)[0].(map[string]any)
+	if got, want := permutation["condition"], "query.block_property('test:open') == 1"; got != want {
+		t.Errorf("expected condition %q, got %q", want, got)
+	}
+	for _, got := range []any{downgraded[0].Properties["components"], permutation["components"]} {
+		if !reflect.DeepEqual(got, components()) {
+			t.Errorf("expected components %v, got %v", components(), got)
+		}
+	}
+
+	geometry := downgraded[0].Properties["components"].(map[string]any)["minecraft:geometry"].(map[string]any)
+	geometry["identifier"] = "geometry.changed"
+	if entries[0].Properties["components"].(map[string]any)["minecraft:geometry"].(map[string]any)["identifier"] != "geometry.test" {
+		t.Errorf("the components of the definitions passed are shared with the definitions returned")
+	}
+}
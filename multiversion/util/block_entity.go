@@ -0,0 +1,92 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/df-mc/dragonfly/server/world"
+	"github.com/oomph-ac/mv/multiversion/chunk"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// TranslateChunkBlockEntities translates the block entities found at the end of the payload of a LevelChunk
+// packet holding count sub chunks, or using one of the sub chunk request modes, by calling f for each of them.
+// The key passed identifies f, so that the translated payload may be shared with other connections using the
+// same protocol. The payload returned is shared and must not be modified.
+func TranslateChunkBlockEntities(conn *minecraft.Conn, payload []byte, count uint32, key uint32, f func(blockEntity map[string]any)) ([]byte, error) {
+	oldFormat := conn.GameData().BaseGameVersion == "1.17.40"
+	translated, _, err := translateChunk(conn, chunkHash(payload, 2, key, count, boolToUint32(oldFormat)), func() ([]byte, uint32, error) {
+		buf := bytes.NewBuffer(payload)
+		subCount := int(count)
+		if count == protocol.SubChunkRequestModeLimited || count == protocol.SubChunkRequestModeLimitless {
+			// Only the biomes are sent in the payload: sub chunks are requested separately.
+			subCount = 0
+		}
+		// The blocks of the chunk are not used, so the runtime ID of air passed does not matter.
+		if _, err := chunk.NetworkDecode(0, buf, subCount, oldFormat, world.Overworld.Range()); err != nil {
+			return nil, 0, err
+		}
+		borderBlocks, err := buf.ReadByte()
+		if err != nil {
+			return nil, 0, fmt.Errorf("read border blocks: %w", err)
+		}
+		if buf.Next(int(borderBlocks)); buf.Len() == 0 {
+			return payload, count, nil
+		}
+		blockEntities, err := translateBlockEntities(buf.Bytes(), f)
+		if err != nil {
+			return nil, 0, err
+		}
+		return append(payload[:len(payload)-buf.Len():len(payload)-buf.Len()], blockEntities...), count, nil
+	})
+	return translated, err
+}
+
+// TranslateSubChunkBlockEntities translates the block entities found in the payload of an entry of a SubChunk
+// packet by calling f for each of them. If cacheEnabled is true, the payload holds only block entities,
+// otherwise they follow the sub chunk. The key passed identifies f, so that the translated payload may be
+// shared with other connections using the same protocol. The payload returned is shared and must not be
+// modified.
+func TranslateSubChunkBlockEntities(conn *minecraft.Conn, payload []byte, cacheEnabled bool, key uint32, f func(blockEntity map[string]any)) ([]byte, error) {
+	translated, _, err := translateChunk(conn, chunkHash(payload, 3, key, boolToUint32(cacheEnabled)), func() ([]byte, uint32, error) {
+		buf := bytes.NewBuffer(payload)
+		if !cacheEnabled {
+			var index byte
+			if _, err := chunk.DecodeSubChunk(0, world.Overworld.Range(), buf, &index, chunk.NetworkEncoding); err != nil {
+				return nil, 0, err
+			}
+		}
+		if buf.Len() == 0 {
+			return payload, 0, nil
+		}
+		blockEntities, err := translateBlockEntities(buf.Bytes(), f)
+		if err != nil {
+			return nil, 0, err
+		}
+		return append(payload[:len(payload)-buf.Len():len(payload)-buf.Len()], blockEntities...), 0, nil
+	})
+	return translated, err
+}
+
+// translateBlockEntities decodes the sequence of block entities passed, calls f for each of them and returns
+// them encoded again.
+func translateBlockEntities(data []byte, f func(blockEntity map[string]any)) ([]byte, error) {
+	buf := bytes.NewBuffer(data)
+	dec := nbt.NewDecoderWithEncoding(buf, nbt.NetworkLittleEndian)
+
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	enc := nbt.NewEncoderWithEncoding(out, nbt.NetworkLittleEndian)
+	for buf.Len() > 0 {
+		var blockEntity map[string]any
+		if err := dec.Decode(&blockEntity); err != nil {
+			return nil, fmt.Errorf("decode block entity: %w", err)
+		}
+		f(blockEntity)
+		if err := enc.Encode(blockEntity); err != nil {
+			return nil, fmt.Errorf("encode block entity: %w", err)
+		}
+	}
+	return out.Bytes(), nil
+}
@@ -0,0 +1,142 @@
+package util
+
+import (
+	"container/list"
+	"encoding/binary"
+	"sync"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// DefaultChunkCacheSize is the default maximum amount of bytes of translated chunk payloads held by the
+// TranslatedChunks cache.
+const DefaultChunkCacheSize = 64 << 20
+
+// TranslatedChunks caches the chunk payloads translated by DefaultDowngrade. It is shared by all connections,
+// so that players using the same protocol in the same area only have each chunk translated once.
+var TranslatedChunks = NewChunkCache(DefaultChunkCacheSize)
+
+// ChunkCache is a bounded cache of translated chunk payloads, keyed by the protocol they were translated for
+// and a hash of the payload they were translated from. Once the payloads held exceed the maximum size of the
+// cache, the least recently used payloads are evicted. A ChunkCache is safe for concurrent use.
+type ChunkCache struct {
+	mu       sync.Mutex
+	maxBytes int
+	bytes    int
+	order    *list.List
+	entries  map[chunkKey]*list.Element
+
+	hits, misses, evictions uint64
+}
+
+// ChunkCacheStats holds statistics on the usage of a ChunkCache.
+type ChunkCacheStats struct {
+	// Hits and Misses are the amount of lookups that did and did not find a cached payload.
+	Hits, Misses uint64
+	// Evictions is the amount of payloads that were removed to make space for new ones.
+	Evictions uint64
+	// Entries and Bytes are the amount of payloads currently cached and their total size.
+	Entries, Bytes int
+}
+
+// chunkKey is the key of a payload in a ChunkCache.
+type chunkKey struct {
+	protocol int32
+	hash     uint64
+}
+
+// chunkEntry is a translated payload held by a ChunkCache.
+type chunkEntry struct {
+	key      chunkKey
+	payload  []byte
+	subCount uint32
+}
+
+// NewChunkCache returns a ChunkCache that holds at most maxBytes bytes of payloads. If maxBytes is 0 or less,
+// nothing is cached.
+func NewChunkCache(maxBytes int) *ChunkCache {
+	return &ChunkCache{maxBytes: maxBytes, order: list.New(), entries: make(map[chunkKey]*list.Element)}
+}
+
+// Resize changes the maximum amount of bytes held by the cache, evicting payloads if needed. If maxBytes is 0
+// or less, the cache is cleared and nothing is cached anymore.
+func (c *ChunkCache) Resize(maxBytes int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.maxBytes = maxBytes
+	c.evict()
+}
+
+// Stats returns statistics on the usage of the cache.
+func (c *ChunkCache) Stats() ChunkCacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return ChunkCacheStats{Hits: c.hits, Misses: c.misses, Evictions: c.evictions, Entries: len(c.entries), Bytes: c.bytes}
+}
+
+// translate returns the payload translated for the protocol passed from the input hashed to hash. If the
+// payload is not yet cached, it is translated using f and added to the cache. The payload returned is shared
+// and must not be modified.
+func (c *ChunkCache) translate(protocol int32, hash uint64, f func() ([]byte, uint32, error)) ([]byte, uint32, error) {
+	key := chunkKey{protocol: protocol, hash: hash}
+
+	c.mu.Lock()
+	if el, ok := c.entries[key]; ok {
+		c.hits++
+		c.order.MoveToFront(el)
+		e := el.Value.(*chunkEntry)
+		c.mu.Unlock()
+		return e.payload, e.subCount, nil
+	}
+	c.misses++
+	c.mu.Unlock()
+
+	// The payload is translated without holding the lock, so that different chunks may be translated at the
+	// same time. Two connections may end up translating the same chunk, in which case the last one is kept.
+	payload, subCount, err := f()
+	if err != nil {
+		return nil, 0, err
+	}
+	payload = payload[:len(payload):len(payload)]
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(payload) > c.maxBytes {
+		return payload, subCount, nil
+	}
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+	c.entries[key] = c.order.PushFront(&chunkEntry{key: key, payload: payload, subCount: subCount})
+	c.bytes += len(payload)
+	c.evict()
+	return payload, subCount, nil
+}
+
+// evict removes the least recently used payloads until the cache holds at most maxBytes bytes.
+func (c *ChunkCache) evict() {
+	for c.bytes > c.maxBytes || (c.maxBytes <= 0 && c.order.Len() > 0) {
+		c.remove(c.order.Back())
+		c.evictions++
+	}
+}
+
+// remove removes the element passed from the cache.
+func (c *ChunkCache) remove(el *list.Element) {
+	e := c.order.Remove(el).(*chunkEntry)
+	delete(c.entries, e.key)
+	c.bytes -= len(e.payload)
+}
+
+// chunkHash hashes a chunk payload together with the values passed, which determine how the payload is
+// translated.
+func chunkHash(payload []byte, values ...uint32) uint64 {
+	d := xxhash.New()
+	b := make([]byte, 4)
+	for _, v := range values {
+		binary.LittleEndian.PutUint32(b, v)
+		_, _ = d.Write(b)
+	}
+	_, _ = d.Write(payload)
+	return d.Sum64()
+}
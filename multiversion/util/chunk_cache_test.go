@@ -0,0 +1,82 @@
+package util
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+// TestChunkCache tests that the ChunkCache shares translated payloads per protocol, evicts the least recently
+// used payloads once full and keeps track of its usage.
+func TestChunkCache(t *testing.T) {
+	c := NewChunkCache(8)
+	translations := 0
+	translate := func(protocol int32, hash uint64, payload string) []byte {
+		got, _, err := c.translate(protocol, hash, func() ([]byte, uint32, error) {
+			translations++
+			return []byte(payload), 0, nil
+		})
+		if err != nil {
+			t.Fatalf("translate: %v", err)
+		}
+		return got
+	}
+
+	translate(589, 1, "aaaa")
+	if got := translate(589, 1, "bbbb"); !bytes.Equal(got, []byte("aaaa")) {
+		t.Errorf("expected cached payload %q, got %q", "aaaa", got)
+	}
+	// The same chunk must be translated again for a different protocol.
+	translate(630, 1, "cccc")
+	if translations != 2 {
+		t.Errorf("expected 2 translations, got %v", translations)
+	}
+
+	// The cache is full, so adding another payload evicts the least recently used one, which was translated
+	// for protocol 589.
+	translate(630, 2, "dddd")
+	if stats := c.Stats(); stats != (ChunkCacheStats{Hits: 1, Misses: 3, Evictions: 1, Entries: 2, Bytes: 8}) {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	translate(589, 1, "eeee")
+	if translations != 4 {
+		t.Errorf("expected evicted payload to be translated again, got %v translations", translations)
+	}
+
+	// Payloads larger than the cache are translated but never cached.
+	translate(589, 3, "ffffffffff")
+	if stats := c.Stats(); stats.Entries != 2 || stats.Bytes != 8 {
+		t.Errorf("expected oversized payload not to be cached, got %+v", stats)
+	}
+
+	c.Resize(0)
+	if stats := c.Stats(); stats.Entries != 0 || stats.Bytes != 0 {
+		t.Errorf("expected empty cache after resizing to 0, got %+v", stats)
+	}
+}
+
+// TestChunkCacheConcurrent tests that the ChunkCache may be used from multiple goroutines at once.
+func TestChunkCacheConcurrent(t *testing.T) {
+	c := NewChunkCache(64)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				hash := uint64((i + j) % 32)
+				payload, _, _ := c.translate(int32(i%2), hash, func() ([]byte, uint32, error) {
+					return []byte{byte(hash), 0, 0, 0}, 0, nil
+				})
+				if payload[0] != byte(hash) {
+					t.Errorf("expected payload for hash %v, got %v", hash, payload)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	if stats := c.Stats(); stats.Hits+stats.Misses != 8000 || stats.Bytes > 64 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
@@ -0,0 +1,61 @@
+package util
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// commandArgIndex is the combination of the flags of a command parameter type that make the lower bits of the
+// type an index into the enums, soft enums or suffixes of the AvailableCommands packet instead of an argument
+// type.
+const commandArgIndex = protocol.CommandArgEnum | protocol.CommandArgSoftEnum | protocol.CommandArgSuffixed
+
+// CommandArgTypes maps the command argument types of one version to the argument types of another version.
+// Argument types not found in the map, such as those of newer versions, are left unchanged.
+type CommandArgTypes map[uint32]uint32
+
+// Inverse returns CommandArgTypes that translate argument types in the opposite direction.
+func (types CommandArgTypes) Inverse() CommandArgTypes {
+	inverse := make(CommandArgTypes, len(types))
+	for t, translated := range types {
+		inverse[translated] = t
+	}
+	return inverse
+}
+
+// Type translates the type of a command parameter. The flags of the type are kept, and types that point to an
+// enum, soft enum or suffix are returned unchanged.
+func (types CommandArgTypes) Type(t uint32) uint32 {
+	if t&protocol.CommandArgValid == 0 || t&commandArgIndex != 0 {
+		return t
+	}
+	if translated, ok := types[t&^protocol.CommandArgValid]; ok {
+		return protocol.CommandArgValid | translated
+	}
+	return t
+}
+
+// Parameters returns a copy of the command parameters passed with their types translated.
+func (types CommandArgTypes) Parameters(params []protocol.CommandParameter) []protocol.CommandParameter {
+	translated := make([]protocol.CommandParameter, len(params))
+	for i, p := range params {
+		p.Type = types.Type(p.Type)
+		translated[i] = p
+	}
+	return translated
+}
+
+// Commands returns a copy of the commands passed with the types of the parameters of their overloads
+// translated.
+func (types CommandArgTypes) Commands(cmds []protocol.Command) []protocol.Command {
+	translated := make([]protocol.Command, len(cmds))
+	for i, c := range cmds {
+		overloads := make([]protocol.CommandOverload, len(c.Overloads))
+		for j, o := range c.Overloads {
+			o.Parameters = types.Parameters(o.Parameters)
+			overloads[j] = o
+		}
+		c.Overloads = overloads
+		translated[i] = c
+	}
+	return translated
+}
@@ -0,0 +1,29 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// TestCommandArgTypes tests that command argument types are translated, while the flags of the types and the
+// indices of enums, soft enums and suffixes are kept.
+func TestCommandArgTypes(t *testing.T) {
+	types := CommandArgTypes{protocol.CommandArgTypeString: 44}
+	tests := []struct{ in, want uint32 }{
+		{in: protocol.CommandArgValid | protocol.CommandArgTypeString, want: protocol.CommandArgValid | 44},
+		{in: protocol.CommandArgValid | protocol.CommandArgTypeInt, want: protocol.CommandArgValid | protocol.CommandArgTypeInt},
+		{in: protocol.CommandArgValid | protocol.CommandArgEnum | protocol.CommandArgTypeString, want: protocol.CommandArgValid | protocol.CommandArgEnum | protocol.CommandArgTypeString},
+		{in: protocol.CommandArgValid | protocol.CommandArgSoftEnum | protocol.CommandArgTypeString, want: protocol.CommandArgValid | protocol.CommandArgSoftEnum | protocol.CommandArgTypeString},
+		{in: protocol.CommandArgSuffixed | protocol.CommandArgTypeString, want: protocol.CommandArgSuffixed | protocol.CommandArgTypeString},
+	}
+	for _, test := range tests {
+		got := types.Type(test.in)
+		if got != test.want {
+			t.Errorf("%#x: expected %#x, got %#x", test.in, test.want, got)
+		}
+		if back := types.Inverse().Type(got); back != test.in {
+			t.Errorf("%#x: expected %#x after translating back, got %#x", test.in, test.in, back)
+		}
+	}
+}
@@ -2,6 +2,10 @@ package util
 
 import (
 	"bytes"
+	"cmp"
+	"fmt"
+	"slices"
+	"sync"
 
 	"github.com/df-mc/dragonfly/server/world"
 	"github.com/oomph-ac/mv/multiversion/chunk"
@@ -13,15 +17,25 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// LatestAirRID is the runtime ID of the air block in the latest version of the game.
-var LatestAirRID, _ = latest.StateToRuntimeID("minecraft:air", nil)
+// LatestAirRID returns the runtime ID of the air block in the latest version of the game.
+var LatestAirRID = sync.OnceValue(func() uint32 {
+	rid, _ := latest.StateToRuntimeID("minecraft:air", nil)
+	return rid
+})
 
-// DowngradeItem downgrades the input item stack to a legacy item stack. It returns a boolean indicating if the item was
-// downgraded successfully.
+// DowngradeItem downgrades the input item stack to a legacy item stack. Vanilla items that do not exist in the
+// legacy version are sent as a minecraft:name_tag, so that the runtime ID sent is always one registered by the
+// client. Component based custom items are returned unchanged, as DowngradeItemEntries keeps their runtime ID.
 func DowngradeItem(input protocol.ItemStack, mappings mappings.MVMapping) protocol.ItemStack {
-	name, _ := latest.ItemRuntimeIDToName(input.NetworkID)
-	networkID, ok := mappings.ItemIDByName(name)
+	networkID, ok := mappings.DowngradeItemRuntimeID(input.NetworkID)
 	if !ok {
+		name, vanilla := latest.ItemRuntimeIDToName(input.NetworkID)
+		if !vanilla {
+			return input
+		}
+		input.ItemType.NetworkID, _ = mappings.ItemIDByName(name)
+		input.ItemType.MetadataValue = 0
+		input.BlockRuntimeID = 0
 		return input
 	}
 
@@ -32,15 +46,94 @@ func DowngradeItem(input protocol.ItemStack, mappings mappings.MVMapping) protoc
 	return input
 }
 
-// UpgradeItem upgrades the input item stack to a latest item stack. It returns a boolean indicating if the item was
-// upgraded successfully.
+// DowngradeItemEntries returns the item entries that a client using the legacy version must register, given the
+// item entries of the latest version sent by the server. They are rebuilt from the item table of the legacy
+// version, so that the runtime IDs of items sent later match those registered. Component based items sent by the
+// server that the legacy version does not hold are kept, unless their runtime ID is already in use.
+func DowngradeItemEntries(entries []protocol.ItemEntry, mappings mappings.MVMapping) []protocol.ItemEntry {
+	return rebuildItemEntries(mappings.Items(), entries, func(name string) bool {
+		_, ok := mappings.ItemIDByName(name)
+		return ok
+	})
+}
+
+// UpgradeItemEntries returns the item entries of the latest version, given the item entries of the legacy version
+// sent by a server. They are rebuilt from the item table of the latest version, keeping component based items
+// that it does not hold, unless their runtime ID is already in use.
+func UpgradeItemEntries(entries []protocol.ItemEntry) []protocol.ItemEntry {
+	return rebuildItemEntries(latest.ItemEntries(), entries, func(name string) bool {
+		_, ok := latest.ItemNameToRuntimeID(name)
+		return ok
+	})
+}
+
+// rebuildItemEntries returns the item entries of an item table, followed by the component based entries passed
+// that the table does not hold, ordered by their runtime ID.
+func rebuildItemEntries(table, entries []protocol.ItemEntry, known func(name string) bool) []protocol.ItemEntry {
+	items := slices.Clone(table)
+	used := make(map[int16]struct{}, len(items))
+	for _, item := range items {
+		used[item.RuntimeID] = struct{}{}
+	}
+	for _, item := range entries {
+		if !item.ComponentBased || known(item.Name) {
+			continue
+		}
+		if _, ok := used[item.RuntimeID]; ok {
+			logrus.Warnf("custom item %v has the runtime ID %v, which is already in use", item.Name, item.RuntimeID)
+			continue
+		}
+		used[item.RuntimeID] = struct{}{}
+		items = append(items, item)
+	}
+	slices.SortFunc(items, func(a, b protocol.ItemEntry) int {
+		return cmp.Compare(a.RuntimeID, b.RuntimeID)
+	})
+	return items
+}
+
+// DowngradeCreativeItems downgrades the creative items passed, as found in the CreativeContent packet. Vanilla
+// items that the legacy version does not hold are dropped, and items that downgrade to the same legacy item as
+// an item before them are dropped too, so that the creative inventory holds no placeholders or duplicates. The
+// remaining items keep their order, so that they are grouped in the creative inventory as sent, and their
+// creative item network IDs.
+func DowngradeCreativeItems(items []protocol.CreativeItem, mappings mappings.MVMapping) []protocol.CreativeItem {
+	type creativeKey struct {
+		networkID, metadata, blockRuntimeID int32
+		nbt                                 string
+	}
+	downgraded := make([]protocol.CreativeItem, 0, len(items))
+	seen := make(map[creativeKey]struct{}, len(items))
+	for _, item := range items {
+		if _, ok := mappings.DowngradeItemRuntimeID(item.Item.NetworkID); !ok {
+			if _, vanilla := latest.ItemRuntimeIDToName(item.Item.NetworkID); vanilla {
+				continue
+			}
+		}
+		item.Item = DowngradeItem(item.Item, mappings)
+
+		key := creativeKey{networkID: item.Item.NetworkID, metadata: int32(item.Item.MetadataValue), blockRuntimeID: item.Item.BlockRuntimeID}
+		if len(item.Item.NBTData) > 0 {
+			// fmt prints maps with sorted keys, so equal NBT always produces the same key.
+			key.nbt = fmt.Sprint(item.Item.NBTData)
+		}
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		downgraded = append(downgraded, item)
+	}
+	return downgraded
+}
+
+// UpgradeItem upgrades the input item stack to a latest item stack. Items that do not exist in the latest version
+// are returned unchanged.
 func UpgradeItem(input protocol.ItemStack, mappings mappings.MVMapping) protocol.ItemStack {
 	if input.ItemType.NetworkID == 0 {
 		return protocol.ItemStack{}
 	}
 
-	name, _ := mappings.ItemNameByID(input.ItemType.NetworkID)
-	networkID, ok := latest.ItemNameToRuntimeID(name)
+	networkID, ok := mappings.UpgradeItemRuntimeID(input.ItemType.NetworkID)
 	if !ok {
 		return input
 	}
@@ -54,26 +147,12 @@ func UpgradeItem(input protocol.ItemStack, mappings mappings.MVMapping) protocol
 
 // DowngradeBlockRuntimeID downgrades a latest block runtime ID to a legacy block runtime ID.
 func DowngradeBlockRuntimeID(input uint32, mappings mappings.MVMapping) uint32 {
-	name, properties, ok := latest.RuntimeIDToState(input)
-	if !ok {
-		return mappings.LegacyAirRID
-	}
-
-	return mappings.StateToRuntimeID(name, properties)
+	return mappings.DowngradeBlockRuntimeID(input)
 }
 
 // UpgradeBlockRuntimeID upgrades a legacy block runtime ID to a latest block runtime ID.
 func UpgradeBlockRuntimeID(input uint32, mappings mappings.MVMapping) uint32 {
-	name, properties, ok := mappings.RuntimeIDToState(input)
-	if !ok {
-		return LatestAirRID
-	}
-
-	runtimeID, ok := latest.StateToRuntimeID(name, properties)
-	if !ok {
-		return LatestAirRID
-	}
-	return runtimeID
+	return mappings.UpgradeBlockRuntimeID(input)
 }
 
 // DefaultUpgrade translates a packet from the legacy version to the latest version.
@@ -122,6 +201,28 @@ func DefaultUpgrade(conn *minecraft.Conn, pk packet.Packet, mapping mappings.MVM
 		pk.Boots.Stack = UpgradeItem(pk.Boots.Stack, mapping)
 	case *packet.MobEquipment:
 		pk.NewItem.Stack = UpgradeItem(pk.NewItem.Stack, mapping)
+	case *packet.AddItemActor:
+		pk.Item.Stack = UpgradeItem(pk.Item.Stack, mapping)
+	case *packet.AddPlayer:
+		pk.HeldItem.Stack = UpgradeItem(pk.HeldItem.Stack, mapping)
+	case *packet.CreativeContent:
+		for i, item := range pk.Items {
+			pk.Items[i].Item = UpgradeItem(item.Item, mapping)
+		}
+	case *packet.InventoryContent:
+		for i, item := range pk.Content {
+			pk.Content[i].Stack = UpgradeItem(item.Stack, mapping)
+		}
+	case *packet.InventorySlot:
+		pk.NewItem.Stack = UpgradeItem(pk.NewItem.Stack, mapping)
+	case *packet.LevelEvent:
+		if pk.EventType == packet.LevelEventParticlesDestroyBlock || pk.EventType == packet.LevelEventParticlesCrackBlock {
+			pk.EventData = int32(UpgradeBlockRuntimeID(uint32(pk.EventData), mapping))
+		}
+	case *packet.LevelSoundEvent:
+		if pk.SoundType == packet.SoundEventPlace || pk.SoundType == packet.SoundEventHit || pk.SoundType == packet.SoundEventItemUseOn || pk.SoundType == packet.SoundEventLand {
+			pk.ExtraData = int32(UpgradeBlockRuntimeID(uint32(pk.ExtraData), mapping))
+		}
 	case *packet.LevelChunk:
 		if pk.SubChunkCount == protocol.SubChunkRequestModeLimited || pk.SubChunkCount == protocol.SubChunkRequestModeLimitless {
 			return pk, true
@@ -135,7 +236,7 @@ func DefaultUpgrade(conn *minecraft.Conn, pk packet.Packet, mapping mappings.MVM
 			return pk, true
 		}
 
-		upgraded := chunk.New(LatestAirRID, r)
+		upgraded := chunk.New(LatestAirRID(), r)
 		for subInd, sub := range c.Sub() {
 			for layerInd, layer := range sub.Layers() {
 				upgradedLayer := upgraded.Sub()[subInd].Layer(uint8(layerInd))
@@ -176,7 +277,7 @@ func DefaultUpgrade(conn *minecraft.Conn, pk packet.Packet, mapping mappings.MVM
 					return pk, true
 				}
 
-				upgraded := chunk.NewSubChunk(LatestAirRID)
+				upgraded := chunk.NewSubChunk(LatestAirRID())
 				for layerInd, layer := range subChunk.Layers() {
 					upgradedLayer := upgraded.Layer(uint8(layerInd))
 					for x := uint8(0); x < 16; x++ {
@@ -202,8 +303,10 @@ func DefaultUpgrade(conn *minecraft.Conn, pk packet.Packet, mapping mappings.MVM
 			pk.Blocks[i].BlockRuntimeID = UpgradeBlockRuntimeID(uint32(block.BlockRuntimeID), mapping)
 		}
 		for i, block := range pk.Extra {
-			pk.Blocks[i].BlockRuntimeID = UpgradeBlockRuntimeID(uint32(block.BlockRuntimeID), mapping)
+			pk.Extra[i].BlockRuntimeID = UpgradeBlockRuntimeID(uint32(block.BlockRuntimeID), mapping)
 		}
+	case *packet.StartGame:
+		pk.Items = UpgradeItemEntries(pk.Items)
 	default:
 		if pk.ID() == 53 {
 			return nil, true
@@ -221,14 +324,54 @@ func DefaultUpgrade(conn *minecraft.Conn, pk packet.Packet, mapping mappings.MVM
 func DefaultDowngrade(conn *minecraft.Conn, pk packet.Packet, mapping mappings.MVMapping) (packet.Packet, bool) {
 	handled := true
 	switch pk := pk.(type) {
+	case *packet.InventoryTransaction:
+		for i, action := range pk.Actions {
+			pk.Actions[i].OldItem.Stack = DowngradeItem(action.OldItem.Stack, mapping)
+			pk.Actions[i].NewItem.Stack = DowngradeItem(action.NewItem.Stack, mapping)
+		}
+		switch data := pk.TransactionData.(type) {
+		case *protocol.UseItemTransactionData:
+			if data.BlockRuntimeID > 0 {
+				data.BlockRuntimeID = DowngradeBlockRuntimeID(data.BlockRuntimeID, mapping)
+			}
+			data.HeldItem.Stack = DowngradeItem(data.HeldItem.Stack, mapping)
+
+			pk.TransactionData = data
+		case *protocol.UseItemOnEntityTransactionData:
+			data.HeldItem.Stack = DowngradeItem(data.HeldItem.Stack, mapping)
+			pk.TransactionData = data
+		case *protocol.ReleaseItemTransactionData:
+			data.HeldItem.Stack = DowngradeItem(data.HeldItem.Stack, mapping)
+			pk.TransactionData = data
+		}
+	case *packet.ItemStackRequest:
+		for i, request := range pk.Requests {
+			var actions = make([]protocol.StackRequestAction, 0)
+			for _, action := range request.Actions {
+				switch data := action.(type) {
+				case *protocol.CraftResultsDeprecatedStackRequestAction:
+					for k, item := range data.ResultItems {
+						data.ResultItems[k] = DowngradeItem(item, mapping)
+					}
+					action = data
+				}
+				actions = append(actions, action)
+			}
+			pk.Requests[i].Actions = actions
+		}
+	case *packet.MobArmourEquipment:
+		pk.Helmet.Stack = DowngradeItem(pk.Helmet.Stack, mapping)
+		pk.Chestplate.Stack = DowngradeItem(pk.Chestplate.Stack, mapping)
+		pk.Leggings.Stack = DowngradeItem(pk.Leggings.Stack, mapping)
+		pk.Boots.Stack = DowngradeItem(pk.Boots.Stack, mapping)
+	case *packet.MobEquipment:
+		pk.NewItem.Stack = DowngradeItem(pk.NewItem.Stack, mapping)
 	case *packet.AddItemActor:
 		pk.Item.Stack = DowngradeItem(pk.Item.Stack, mapping)
 	case *packet.AddPlayer:
 		pk.HeldItem.Stack = DowngradeItem(pk.HeldItem.Stack, mapping)
 	case *packet.CreativeContent:
-		for i, item := range pk.Items {
-			pk.Items[i].Item = DowngradeItem(item.Item, mapping)
-		}
+		pk.Items = DowngradeCreativeItems(pk.Items, mapping)
 	case *packet.InventoryContent:
 		for i, item := range pk.Content {
 			pk.Content[i].Stack = DowngradeItem(item.Stack, mapping)
@@ -248,70 +391,27 @@ func DefaultDowngrade(conn *minecraft.Conn, pk packet.Packet, mapping mappings.M
 			return pk, true
 		}
 
-		r := world.Overworld.Range()
-		buff := bytes.NewBuffer(pk.RawPayload)
-		c, err := chunk.NetworkDecode(LatestAirRID, buff, int(pk.SubChunkCount), conn.GameData().BaseGameVersion == "1.17.40", r)
+		oldFormat := conn.GameData().BaseGameVersion == "1.17.40"
+		payload, count, err := translateChunk(conn, chunkHash(pk.RawPayload, 0, pk.SubChunkCount, boolToUint32(oldFormat)), func() ([]byte, uint32, error) {
+			return downgradeChunk(pk.RawPayload, pk.SubChunkCount, oldFormat, mapping)
+		})
 		if err != nil {
 			logrus.Error(err)
 			return pk, true
 		}
-
-		downgraded := chunk.New(mapping.LegacyAirRID, r)
-		for subInd, sub := range c.Sub() {
-			for layerInd, layer := range sub.Layers() {
-				downgradedLayer := downgraded.Sub()[subInd].Layer(uint8(layerInd))
-				for x := uint8(0); x < 16; x++ {
-					for z := uint8(0); z < 16; z++ {
-						for y := uint8(0); y < 16; y++ {
-							latestRuntimeID := layer.At(x, y, z)
-							downgradedLayer.Set(x, y, z, DowngradeBlockRuntimeID(latestRuntimeID, mapping))
-						}
-					}
-				}
-			}
-		}
-		for x := uint8(0); x < 16; x++ {
-			for z := uint8(0); z < 16; z++ {
-				y := c.HighestBlock(x, z)
-				downgraded.SetBiome(x, y, z, c.Biome(x, y, z))
-			}
-		}
-
-		data := chunk.Encode(downgraded, chunk.NetworkEncoding, r)
-		chunkBuf := bytes.NewBuffer(nil)
-		for i := range data.SubChunks {
-			chunkBuf.Write(data.SubChunks[i])
-		}
-		chunkBuf.Write(data.Biomes)
-
-		pk.SubChunkCount = uint32(len(data.SubChunks))
-		pk.RawPayload = append(chunkBuf.Bytes(), buff.Bytes()...)
+		pk.SubChunkCount, pk.RawPayload = count, payload
 	case *packet.SubChunk:
 		for i, entry := range pk.SubChunkEntries {
 			if entry.Result == protocol.SubChunkResultSuccess && !pk.CacheEnabled {
-				buff := bytes.NewBuffer(entry.RawPayload)
-				var index byte = 0
-				subChunk, err := chunk.DecodeSubChunk(LatestAirRID, world.Overworld.Range(), buff, &index, chunk.NetworkEncoding)
+				ind := int16(pk.Position.Y()) + int16(entry.Offset[1]) - int16(world.Overworld.Range()[0])>>4
+				payload, _, err := translateChunk(conn, chunkHash(entry.RawPayload, 1, uint32(ind)), func() ([]byte, uint32, error) {
+					return downgradeSubChunk(entry.RawPayload, ind, mapping)
+				})
 				if err != nil {
 					logrus.Error(err)
 					return pk, true
 				}
-
-				downgraded := chunk.NewSubChunk(mapping.LegacyAirRID)
-				for layerInd, layer := range subChunk.Layers() {
-					downgradedLayer := downgraded.Layer(uint8(layerInd))
-					for x := uint8(0); x < 16; x++ {
-						for z := uint8(0); z < 16; z++ {
-							for y := uint8(0); y < 16; y++ {
-								latestRuntimeID := layer.At(x, y, z)
-								downgradedLayer.Set(x, y, z, DowngradeBlockRuntimeID(latestRuntimeID, mapping))
-							}
-						}
-					}
-				}
-				ind := int16(pk.Position.Y()) + int16(entry.Offset[1]) - int16(world.Overworld.Range()[0])>>4
-				serialised := chunk.EncodeSubChunk(downgraded, chunk.NetworkEncoding, world.Overworld.Range(), int(ind))
-				pk.SubChunkEntries[i].RawPayload = append(serialised, buff.Bytes()...)
+				pk.SubChunkEntries[i].RawPayload = payload
 			}
 		}
 	case *packet.UpdateBlock:
@@ -323,38 +423,102 @@ func DefaultDowngrade(conn *minecraft.Conn, pk packet.Packet, mapping mappings.M
 			pk.Blocks[i].BlockRuntimeID = DowngradeBlockRuntimeID(block.BlockRuntimeID, mapping)
 		}
 		for i, block := range pk.Extra {
-			pk.Blocks[i].BlockRuntimeID = DowngradeBlockRuntimeID(block.BlockRuntimeID, mapping)
+			pk.Extra[i].BlockRuntimeID = DowngradeBlockRuntimeID(block.BlockRuntimeID, mapping)
 		}
 	case *packet.CraftingData: // TODO: Fix crafting later, this keeps crashing the client.
 		return &packet.CraftingData{
 			ClearRecipes: true,
 		}, true
 	case *packet.StartGame:
-		items := make([]protocol.ItemEntry, 0, len(pk.Items))
-		for _, item := range pk.Items {
-			id, ok := latest.ItemNameToRuntimeID(item.Name)
-			if !ok {
-				items = append(items, item)
-				continue
-			}
+		pk.Items = DowngradeItemEntries(pk.Items, mapping)
+	default:
+		handled = false
+	}
 
-			name, ok := mapping.ItemNameByID(id)
-			if !ok {
-				items = append(items, item)
-				continue
-			}
+	return pk, handled
+}
+
+// translateChunk translates a chunk payload using f, sharing the result with other connections using the same
+// protocol through TranslatedChunks.
+func translateChunk(conn *minecraft.Conn, hash uint64, f func() ([]byte, uint32, error)) ([]byte, uint32, error) {
+	proto := conn.Protocol()
+	if proto == nil {
+		return f()
+	}
+	return TranslatedChunks.translate(proto.ID(), hash, f)
+}
 
-			items = append(items, protocol.ItemEntry{
-				Name:           name,
-				RuntimeID:      item.RuntimeID,
-				ComponentBased: item.ComponentBased,
-			})
+// downgradeChunk downgrades the payload of a LevelChunk packet holding count sub chunks. It returns the
+// downgraded payload and the amount of sub chunks it holds.
+func downgradeChunk(payload []byte, count uint32, oldFormat bool, mapping mappings.MVMapping) ([]byte, uint32, error) {
+	r := world.Overworld.Range()
+	buff := bytes.NewBuffer(payload)
+	c, err := chunk.NetworkDecode(LatestAirRID(), buff, int(count), oldFormat, r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	downgraded := chunk.New(mapping.LegacyAirRID, r)
+	for subInd, sub := range c.Sub() {
+		for layerInd, layer := range sub.Layers() {
+			downgradedLayer := downgraded.Sub()[subInd].Layer(uint8(layerInd))
+			for x := uint8(0); x < 16; x++ {
+				for z := uint8(0); z < 16; z++ {
+					for y := uint8(0); y < 16; y++ {
+						latestRuntimeID := layer.At(x, y, z)
+						downgradedLayer.Set(x, y, z, DowngradeBlockRuntimeID(latestRuntimeID, mapping))
+					}
+				}
+			}
 		}
+	}
+	for x := uint8(0); x < 16; x++ {
+		for z := uint8(0); z < 16; z++ {
+			y := c.HighestBlock(x, z)
+			downgraded.SetBiome(x, y, z, c.Biome(x, y, z))
+		}
+	}
 
-		pk.Items = items
-	default:
-		handled = false
+	data := chunk.Encode(downgraded, chunk.NetworkEncoding, r)
+	chunkBuf := bytes.NewBuffer(nil)
+	for i := range data.SubChunks {
+		chunkBuf.Write(data.SubChunks[i])
 	}
+	chunkBuf.Write(data.Biomes)
 
-	return pk, handled
+	return append(chunkBuf.Bytes(), buff.Bytes()...), uint32(len(data.SubChunks)), nil
+}
+
+// downgradeSubChunk downgrades the payload of a sub chunk in a SubChunk packet, found at the index ind in its
+// chunk.
+func downgradeSubChunk(payload []byte, ind int16, mapping mappings.MVMapping) ([]byte, uint32, error) {
+	buff := bytes.NewBuffer(payload)
+	var index byte = 0
+	subChunk, err := chunk.DecodeSubChunk(LatestAirRID(), world.Overworld.Range(), buff, &index, chunk.NetworkEncoding)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	downgraded := chunk.NewSubChunk(mapping.LegacyAirRID)
+	for layerInd, layer := range subChunk.Layers() {
+		downgradedLayer := downgraded.Layer(uint8(layerInd))
+		for x := uint8(0); x < 16; x++ {
+			for z := uint8(0); z < 16; z++ {
+				for y := uint8(0); y < 16; y++ {
+					latestRuntimeID := layer.At(x, y, z)
+					downgradedLayer.Set(x, y, z, DowngradeBlockRuntimeID(latestRuntimeID, mapping))
+				}
+			}
+		}
+	}
+	serialised := chunk.EncodeSubChunk(downgraded, chunk.NetworkEncoding, world.Overworld.Range(), int(ind))
+	return append(serialised, buff.Bytes()...), 0, nil
+}
+
+// boolToUint32 returns 1 if b is true and 0 otherwise.
+func boolToUint32(b bool) uint32 {
+	if b {
+		return 1
+	}
+	return 0
 }
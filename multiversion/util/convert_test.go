@@ -0,0 +1,110 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/oomph-ac/mv/multiversion/latest"
+	"github.com/oomph-ac/mv/multiversion/mappings"
+	"github.com/sandertv/gophertunnel/minecraft/nbt"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// TestDowngradeItemEntries tests that the item entries of the StartGame packet are rebuilt from the legacy item
+// table, keeping only custom items that the legacy version does not hold and that do not collide with it.
+func TestDowngradeItemEntries(t *testing.T) {
+	data, err := nbt.Marshal(map[string]int32{"minecraft:stone": 1, "minecraft:name_tag": 2, "minecraft:legacy_only": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mapping := mappings.Mapping(latest.BlockStateData, data, false)
+
+	stone, _ := latest.ItemNameToRuntimeID("minecraft:stone")
+	got := DowngradeItemEntries([]protocol.ItemEntry{
+		{Name: "minecraft:stone", RuntimeID: int16(stone)},
+		{Name: "minecraft:crafter", RuntimeID: 1000},
+		{Name: "test:custom", RuntimeID: 2000, ComponentBased: true},
+		{Name: "test:collision", RuntimeID: 3, ComponentBased: true},
+	}, mapping)
+
+	want := []protocol.ItemEntry{
+		{Name: "minecraft:stone", RuntimeID: 1},
+		{Name: "minecraft:name_tag", RuntimeID: 2},
+		{Name: "minecraft:legacy_only", RuntimeID: 3},
+		{Name: "test:custom", RuntimeID: 2000, ComponentBased: true},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v item entries, got %v: %v", len(want), len(got), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("item entry %v: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+}
+
+// TestDowngradeCreativeItems tests that creative items unknown to the legacy version and items that collapse to
+// the same legacy item are dropped, keeping the order and network IDs of the other items.
+func TestDowngradeCreativeItems(t *testing.T) {
+	data, err := nbt.Marshal(map[string]int32{"minecraft:stone": 1, "minecraft:name_tag": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mapping := mappings.Mapping(latest.BlockStateData, data, false)
+
+	stone, _ := latest.ItemNameToRuntimeID("minecraft:stone")
+	crafter, _ := latest.ItemNameToRuntimeID("minecraft:crafter")
+	nameTag, _ := latest.ItemNameToRuntimeID("minecraft:name_tag")
+	item := func(id uint32, networkID int32, nbt map[string]any) protocol.CreativeItem {
+		return protocol.CreativeItem{CreativeItemNetworkID: id, Item: protocol.ItemStack{ItemType: protocol.ItemType{NetworkID: networkID}, NBTData: nbt}}
+	}
+	got := DowngradeCreativeItems([]protocol.CreativeItem{
+		item(1, nameTag, nil),
+		item(2, crafter, nil),
+		item(3, stone, nil),
+		item(4, stone, nil),
+		item(5, stone, map[string]any{"display": map[string]any{"Name": "Stone"}}),
+		item(6, 5000, nil),
+	}, mapping)
+
+	want := []protocol.CreativeItem{
+		item(1, 2, nil),
+		item(3, 1, nil),
+		item(5, 1, map[string]any{"display": map[string]any{"Name": "Stone"}}),
+		item(6, 5000, nil),
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v creative items, got %v: %+v", len(want), len(got), got)
+	}
+	for i := range want {
+		if got[i].CreativeItemNetworkID != want[i].CreativeItemNetworkID || got[i].Item.NetworkID != want[i].Item.NetworkID {
+			t.Errorf("creative item %v: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+}
+
+// TestDowngradeItem tests that vanilla items the legacy version does not hold are sent as a name tag, while
+// custom items keep their runtime ID.
+func TestDowngradeItem(t *testing.T) {
+	data, err := nbt.Marshal(map[string]int32{"minecraft:stone": 1, "minecraft:name_tag": 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mapping := mappings.Mapping(latest.BlockStateData, data, false)
+
+	stone, _ := latest.ItemNameToRuntimeID("minecraft:stone")
+	crafter, _ := latest.ItemNameToRuntimeID("minecraft:crafter")
+	for _, test := range []struct {
+		name      string
+		networkID int32
+		want      int32
+	}{
+		{name: "minecraft:stone", networkID: stone, want: 1},
+		{name: "minecraft:crafter", networkID: crafter, want: 2},
+		{name: "test:custom", networkID: 5000, want: 5000},
+	} {
+		item := protocol.ItemStack{ItemType: protocol.ItemType{NetworkID: test.networkID, MetadataValue: 3}, Count: 1}
+		if got := DowngradeItem(item, mapping); got.NetworkID != test.want {
+			t.Errorf("%v: expected network ID %v, got %v", test.name, test.want, got.NetworkID)
+		}
+	}
+}
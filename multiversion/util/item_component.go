@@ -0,0 +1,56 @@
+package util
+
+import (
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// TranslateItemComponents returns the custom item components passed, as found in the ItemComponent packet, with
+// f called on a copy of the data of each of them. The components passed are not modified.
+func TranslateItemComponents(entries []protocol.ItemComponentEntry, f func(data map[string]any)) []protocol.ItemComponentEntry {
+	translated := make([]protocol.ItemComponentEntry, len(entries))
+	for i, entry := range entries {
+		data := cloneNBT(entry.Data).(map[string]any)
+		f(data)
+		translated[i] = protocol.ItemComponentEntry{Name: entry.Name, Data: data}
+	}
+	return translated
+}
+
+// DowngradeItemIcon converts the minecraft:icon property of the item component data passed to the format used
+// before 1.20.60, which holds a single texture instead of a texture for each variant of the item. Other
+// components of the item are left as they are, so servers sending custom items to versions before 1.20.60 must
+// only use components that those versions support.
+func DowngradeItemIcon(data map[string]any) {
+	icon, ok := itemProperties(data)["minecraft:icon"].(map[string]any)
+	if !ok {
+		return
+	}
+	textures, ok := icon["textures"].(map[string]any)
+	if !ok {
+		return
+	}
+	delete(icon, "textures")
+	icon["texture"], _ = textures["default"].(string)
+}
+
+// UpgradeItemIcon converts the minecraft:icon property of the item component data passed from the format used
+// before 1.20.60 to the latest format.
+func UpgradeItemIcon(data map[string]any) {
+	icon, ok := itemProperties(data)["minecraft:icon"].(map[string]any)
+	if !ok {
+		return
+	}
+	texture, ok := icon["texture"].(string)
+	if !ok {
+		return
+	}
+	delete(icon, "texture")
+	icon["textures"] = map[string]any{"default": texture}
+}
+
+// itemProperties returns the item_properties of the item component data passed, or nil if it has none.
+func itemProperties(data map[string]any) map[string]any {
+	components, _ := data["components"].(map[string]any)
+	properties, _ := components["item_properties"].(map[string]any)
+	return properties
+}
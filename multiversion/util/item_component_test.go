@@ -0,0 +1,25 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+)
+
+// TestDowngradeItemIcon tests that the icon of a custom item is converted to the format with a single texture
+// without modifying the components passed.
+func TestDowngradeItemIcon(t *testing.T) {
+	icon := map[string]any{"textures": map[string]any{"default": "item"}}
+	entries := []protocol.ItemComponentEntry{{Name: "test:item", Data: map[string]any{
+		"components": map[string]any{"item_properties": map[string]any{"minecraft:icon": icon}},
+	}}}
+
+	downgraded := TranslateItemComponents(entries, DowngradeItemIcon)
+	got := itemProperties(downgraded[0].Data)["minecraft:icon"].(map[string]any)
+	if got["texture"] != "item" || got["textures"] != nil {
+		t.Errorf("unexpected downgraded icon %v", got)
+	}
+	if _, ok := icon["textures"]; !ok {
+		t.Errorf("the components passed were modified")
+	}
+}
@@ -0,0 +1,45 @@
+package util
+
+import (
+	"fmt"
+
+	"github.com/oomph-ac/mv/multiversion/mappings"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/sirupsen/logrus"
+)
+
+// UpgradePacket converts a packet read from a legacy connection to the latest version. The upgrade function passed
+// first converts the packet to the packets of the latest version, after which DefaultUpgrade translates the
+// runtime IDs they hold.
+func UpgradePacket(conn *minecraft.Conn, pk packet.Packet, mapping mappings.MVMapping, upgrade func([]packet.Packet, *minecraft.Conn) []packet.Packet) []packet.Packet {
+	packets := []packet.Packet{}
+	for _, pk := range upgrade([]packet.Packet{pk}, conn) {
+		if upgraded, ok := DefaultUpgrade(conn, pk, mapping); ok {
+			if upgraded != nil {
+				packets = append(packets, upgraded)
+			}
+			continue
+		}
+		packets = append(packets, pk)
+	}
+	return packets
+}
+
+// RecoverMalformed recovers from a panic that occurred while converting the packet passed to the latest version. It
+// must be deferred by implementations of minecraft.Protocol.ConvertToLatest. Packets sent by a client are not
+// trusted, so a malformed packet must never bring down the goroutine of the connection: Instead, the packet is
+// dropped and the connection that sent it is closed.
+func RecoverMalformed(conn *minecraft.Conn, pk packet.Packet, packets *[]packet.Packet) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	*packets = nil
+	logrus.Errorf("malformed packet %T: %v", pk, r)
+	if conn != nil {
+		if err := conn.Close(); err != nil {
+			logrus.Error(fmt.Errorf("close connection after malformed packet: %w", err))
+		}
+	}
+}
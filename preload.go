@@ -0,0 +1,20 @@
+package vers
+
+import (
+	"github.com/oomph-ac/mv/multiversion/latest"
+	"github.com/oomph-ac/mv/multiversion/mappings"
+	"github.com/sandertv/gophertunnel/minecraft"
+)
+
+// Preload loads the block and item mappings of the protocols passed. Mappings are otherwise loaded when they are
+// first used, which is usually once the first client using a protocol joins. Protocols that do not hold any
+// mappings, such as the latest protocol, are ignored.
+func Preload(protocols ...minecraft.Protocol) {
+	// Every mapping is translated from and to the latest version, so its mappings are always needed.
+	latest.BlockCount()
+	for _, proto := range protocols {
+		if p, ok := proto.(interface{ Mapping() mappings.MVMapping }); ok {
+			p.Mapping()
+		}
+	}
+}
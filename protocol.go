@@ -0,0 +1,65 @@
+package vers
+
+import (
+	"sync"
+
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/session"
+	"github.com/google/uuid"
+	"github.com/sandertv/gophertunnel/minecraft"
+)
+
+var (
+	// connMu guards conns.
+	connMu sync.RWMutex
+	// conns holds every connection currently accepted by a Vers listener, indexed by its UUID.
+	conns = map[uuid.UUID]*conn{}
+)
+
+// ConnProtocol returns the minecraft.Protocol the session.Conn passed is communicating with. The protocol
+// holds both the protocol ID and the version string of the client. False is returned if the protocol of the
+// connection could not be found.
+func ConnProtocol(conn session.Conn) (minecraft.Protocol, bool) {
+	if c, ok := conn.(interface{ Protocol() minecraft.Protocol }); ok {
+		return unwrapProtocol(c.Protocol()), true
+	}
+	id, err := uuid.Parse(conn.IdentityData().Identity)
+	if err != nil {
+		return nil, false
+	}
+	return protocolByUUID(id)
+}
+
+// PlayerProtocol returns the minecraft.Protocol of the client controlling the player passed. False is returned
+// if the player did not join through a Vers listener or has since disconnected.
+func PlayerProtocol(p *player.Player) (minecraft.Protocol, bool) {
+	return protocolByUUID(p.UUID())
+}
+
+// protocolByUUID looks up the protocol of an accepted connection by its UUID.
+func protocolByUUID(id uuid.UUID) (minecraft.Protocol, bool) {
+	connMu.RLock()
+	defer connMu.RUnlock()
+	c, ok := conns[id]
+	if !ok {
+		return nil, false
+	}
+	return c.Protocol(), true
+}
+
+// registerConn stores the connection passed so that its protocol may be looked up later.
+func registerConn(c *conn) {
+	connMu.Lock()
+	defer connMu.Unlock()
+	conns[c.id] = c
+}
+
+// unregisterConn removes the connection passed, if it is still the one registered for its UUID. A player
+// logging in from another location may already have replaced it.
+func unregisterConn(c *conn) {
+	connMu.Lock()
+	defer connMu.Unlock()
+	if conns[c.id] == c {
+		delete(conns, c.id)
+	}
+}
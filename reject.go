@@ -0,0 +1,201 @@
+package vers
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+
+	v618packet "github.com/oomph-ac/mv/multiversion/mv618/packet"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// releasedProtocols holds the protocols of the released versions of the game that are rejected with a message
+// if they are not accepted, starting at 1.19.30, the first version to request network settings before logging
+// in. Clients using other protocols are rejected with the generic message of the game.
+var releasedProtocols = []int32{
+	554, 557, 560, 567, 568, 575, 582, 589, 594, 618, 622, 630, 649, 662, 671,
+	685, 686, 712, 729, 748, 766, 776, 786, 800, 818, 819, 827, 844, 859, 860,
+}
+
+const (
+	// defaultRejectMessage is the default message shown to clients with an unsupported protocol. The
+	// supported versions are formatted into it.
+	defaultRejectMessage = "Your version of Minecraft is not supported by this server. Please join using one of the following versions: %v."
+)
+
+// rejecter rejects clients with a protocol that is not accepted by a Vers listener, showing them a message with
+// the versions that are supported.
+type rejecter struct {
+	v *Vers
+
+	mu    sync.Mutex
+	count map[int32]uint64
+}
+
+// protocols returns a rejectProtocol for every released protocol, other than the latest protocol, that is not
+// present in the protocols passed. The message passed is formatted with the versions that are accepted.
+func (r *rejecter) protocols(accepted []minecraft.Protocol, format string) []minecraft.Protocol {
+	versions := []string{protocol.CurrentVersion}
+	for _, p := range accepted {
+		if !slices.Contains(versions, p.Ver()) {
+			versions = append(versions, p.Ver())
+		}
+	}
+	slices.SortFunc(versions, compareVersions)
+	message := fmt.Sprintf(format, strings.Join(versions, ", "))
+
+	var protocols []minecraft.Protocol
+	for _, id := range releasedProtocols {
+		if id == protocol.CurrentProtocol || slices.ContainsFunc(accepted, func(p minecraft.Protocol) bool { return p.ID() == id }) {
+			continue
+		}
+		protocols = append(protocols, rejectProtocol{id: id, message: message, r: r})
+	}
+	return protocols
+}
+
+// reject records a client with the protocol passed being rejected and calls the Handler of the Vers instance, if
+// it implements UnsupportedProtocolHandler.
+func (r *rejecter) reject(addr net.Addr, id int32) {
+	r.mu.Lock()
+	r.count[id]++
+	r.mu.Unlock()
+
+	if h, ok := r.v.handler().(UnsupportedProtocolHandler); ok {
+		h.HandleUnsupportedProtocol(addr, id)
+	}
+}
+
+// compareVersions compares two versions such as "1.20.0" and "1.20.10" by their numeric components.
+func compareVersions(a, b string) int {
+	x, y := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(x) && i < len(y); i++ {
+		var m, n int
+		_, _ = fmt.Sscan(x[i], &m)
+		_, _ = fmt.Sscan(y[i], &n)
+		if m != n {
+			return m - n
+		}
+	}
+	return len(x) - len(y)
+}
+
+// rejectProtocol is a minecraft.Protocol accepted for a protocol that is not supported. Instead of finishing
+// the login sequence, it sends a disconnect with a message to the client as soon as it logs in.
+type rejectProtocol struct {
+	id      int32
+	message string
+	r       *rejecter
+}
+
+func (p rejectProtocol) ID() int32 {
+	return p.id
+}
+
+func (p rejectProtocol) Ver() string {
+	return "unsupported"
+}
+
+func (rejectProtocol) NewReader(r minecraft.ByteReader, shieldID int32, enableLimits bool) protocol.IO {
+	return protocol.NewReader(r, shieldID, enableLimits)
+}
+
+func (rejectProtocol) NewWriter(w minecraft.ByteWriter, shieldID int32) protocol.IO {
+	return protocol.NewWriter(w, shieldID)
+}
+
+func (rejectProtocol) Packets(bool) packet.Pool {
+	// Only the login is ever read: The client is disconnected when it arrives, and any other packet closes the
+	// connection.
+	return packet.Pool{
+		packet.IDLogin: func() packet.Packet { return &packet.Login{} },
+	}
+}
+
+func (rejectProtocol) Encryption(key [32]byte) packet.Encryption {
+	return packet.NewCTREncryption(key[:])
+}
+
+func (p rejectProtocol) ConvertToLatest(pk packet.Packet, conn *minecraft.Conn) []packet.Packet {
+	if _, ok := pk.(*packet.Login); !ok {
+		return []packet.Packet{pk}
+	}
+	// The network settings are sent as usual, so that the disconnect is written using the compression the
+	// client expects. The login is dropped and the client is disconnected instead.
+	p.r.reject(conn.RemoteAddr(), p.id)
+	_ = conn.WritePacket(&packet.Disconnect{Message: p.message})
+	_ = conn.Flush()
+	return nil
+}
+
+func (p rejectProtocol) ConvertFromLatest(pk packet.Packet, _ *minecraft.Conn) []packet.Packet {
+	if _, ok := pk.(*packet.Disconnect); ok {
+		return []packet.Packet{p.disconnect()}
+	}
+	return []packet.Packet{pk}
+}
+
+// disconnect returns the Disconnect packet sent to clients using the protocol.
+func (p rejectProtocol) disconnect() packet.Packet {
+	if p.id < 622 {
+		// The reason of a disconnect was added in 1.20.40.
+		return &v618packet.Disconnect{Message: p.message}
+	}
+	if p.id >= 712 {
+		// A filtered message was added in 1.21.20.
+		return &filteredDisconnect{Message: p.message, FilteredMessage: p.message}
+	}
+	return &packet.Disconnect{Message: p.message}
+}
+
+// filteredDisconnect is the Disconnect packet of 1.21.20 and later, which is newer than the latest protocol
+// supported and is only sent to reject clients using these versions.
+type filteredDisconnect struct {
+	// Reason is the reason for the disconnection.
+	Reason int32
+	// HideDisconnectionScreen specifies if the disconnection screen should be hidden when the client is
+	// disconnected, meaning it will be sent directly to the main menu.
+	HideDisconnectionScreen bool
+	// Message is an optional message to show when disconnected.
+	Message string
+	// FilteredMessage is the message shown to clients with profanity filtering enabled.
+	FilteredMessage string
+}
+
+// ID ...
+func (*filteredDisconnect) ID() uint32 {
+	return packet.IDDisconnect
+}
+
+func (pk *filteredDisconnect) Marshal(io protocol.IO) {
+	io.Varint32(&pk.Reason)
+	io.Bool(&pk.HideDisconnectionScreen)
+	if !pk.HideDisconnectionScreen {
+		io.String(&pk.Message)
+		io.String(&pk.FilteredMessage)
+	}
+}
+
+// WithRejectMessage sets the message shown to clients with a protocol that is not accepted. The message is
+// formatted with a comma separated list of the versions that are supported, using fmt.Sprintf.
+func WithRejectMessage(message string) Option {
+	return func(o *options) {
+		o.rejectMessage = message
+	}
+}
+
+// Rejections returns the amount of clients rejected by the listeners of the Vers instance, indexed by the
+// protocol they attempted to join with.
+func (v *Vers) Rejections() map[int32]uint64 {
+	v.r.mu.Lock()
+	defer v.r.mu.Unlock()
+	m := make(map[int32]uint64, len(v.r.count))
+	for id, n := range v.r.count {
+		m[id] = n
+	}
+	return m
+}
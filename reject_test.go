@@ -0,0 +1,155 @@
+package vers
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oomph-ac/mv/multiversion/mv589"
+	"github.com/oomph-ac/mv/multiversion/mv618"
+	v618packet "github.com/oomph-ac/mv/multiversion/mv618/packet"
+	"github.com/oomph-ac/mv/multiversion/mv662"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// TestRejectProtocols tests that every released protocol that is not accepted is rejected with a message
+// listing the versions that are accepted.
+func TestRejectProtocols(t *testing.T) {
+	v := New()
+	protocols := v.r.protocols([]minecraft.Protocol{mv589.Protocol{}}, defaultRejectMessage)
+	if len(protocols) != len(releasedProtocols)-2 {
+		t.Errorf("expected %v protocols, got %v", len(releasedProtocols)-2, len(protocols))
+	}
+	for _, p := range protocols {
+		if p.ID() == 589 || p.ID() == protocol.CurrentProtocol {
+			t.Errorf("accepted protocol %v is rejected", p.ID())
+		}
+		want := "Please join using one of the following versions: 1.20.0, " + protocol.CurrentVersion + "."
+		if msg := p.(rejectProtocol).message; !strings.HasSuffix(msg, want) {
+			t.Errorf("expected the message to end with %q, got %q", want, msg)
+		}
+	}
+}
+
+// TestRejectDisconnect tests that clients before 1.20.40 are sent a Disconnect packet without a reason, and that
+// clients from 1.21.20 on are sent one with a filtered message.
+func TestRejectDisconnect(t *testing.T) {
+	if pk, ok := (rejectProtocol{id: 618, message: "msg"}).disconnect().(*v618packet.Disconnect); !ok || pk.Message != "msg" {
+		t.Errorf("expected a 1.20.30 Disconnect packet with the message, got %#v", pk)
+	}
+	if pk, ok := (rejectProtocol{id: 622, message: "msg"}).disconnect().(*packet.Disconnect); !ok || pk.Message != "msg" {
+		t.Errorf("expected a Disconnect packet with the message, got %#v", pk)
+	}
+	if pk, ok := (rejectProtocol{id: 712, message: "msg"}).disconnect().(*filteredDisconnect); !ok || pk.Message != "msg" || pk.FilteredMessage != "msg" {
+		t.Errorf("expected a 1.21.20 Disconnect packet with the message, got %#v", pk)
+	}
+
+	buf := bytes.NewBuffer(nil)
+	(rejectProtocol{id: 860, message: "msg"}).disconnect().Marshal(protocol.NewWriter(buf, 0))
+	if want := []byte{0, 0, 3, 'm', 's', 'g', 3, 'm', 's', 'g'}; !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("expected the Disconnect of protocol 860 to be encoded as %v, got %v", want, buf.Bytes())
+	}
+}
+
+// TestRejections tests that clients joining with a protocol that is not accepted are disconnected with the
+// message and counted.
+func TestRejections(t *testing.T) {
+	v := New()
+	conf := minecraft.ListenConfig{AuthenticationDisabled: true}
+	conf.AcceptedProtocols = v.r.protocols([]minecraft.Protocol{mv589.Protocol{}}, "Unsupported: %v")
+	l, err := conf.Listen(LoopbackNetwork, "127.0.0.1:19141")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = l.Close()
+	})
+
+	for _, proto := range []minecraft.Protocol{mv618.Protocol{}, mv662.Protocol{}, mv662.Protocol{}} {
+		message, err := testRejectJoin(proto, "127.0.0.1:19141")
+		if err != nil {
+			t.Errorf("%v: %v", proto.Ver(), err)
+		} else if want := "Unsupported: 1.20.0, " + protocol.CurrentVersion; message != want {
+			t.Errorf("%v: expected to be disconnected with %q, got %q", proto.Ver(), want, message)
+		}
+	}
+	if got := v.Rejections(); got[618] != 1 || got[662] != 2 {
+		t.Errorf("expected 1 rejection of 618 and 2 of 662, got %v", got)
+	}
+}
+
+// testRejectJoin joins the address passed using the protocol passed and returns the message of the Disconnect
+// packet that the client is sent after logging in. The login sequence is written by hand rather than using a
+// minecraft.Dialer, as the Dialer may flush a nil Conn after a dial fails.
+func testRejectJoin(proto minecraft.Protocol, address string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+	c, err := loopbackNetwork{}.DialContext(ctx, address)
+	if err != nil {
+		return "", err
+	}
+	defer c.Close()
+	_ = c.SetDeadline(time.Now().Add(time.Second * 10))
+
+	enc, dec := packet.NewEncoder(c), packet.NewDecoder(c)
+	write := func(pk packet.Packet) error {
+		buf := bytes.NewBuffer(nil)
+		(&packet.Header{PacketID: pk.ID()}).Write(buf)
+		pk.Marshal(proto.NewWriter(buf, 0))
+		return enc.Encode([][]byte{buf.Bytes()})
+	}
+	read := func() (packet.Packet, error) {
+		data, err := dec.Decode()
+		if err != nil {
+			return nil, err
+		}
+		buf := bytes.NewBuffer(data[0])
+		var hdr packet.Header
+		if err := hdr.Read(buf); err != nil {
+			return nil, err
+		}
+		pkFunc, ok := proto.Packets(false)[hdr.PacketID]
+		if !ok {
+			return nil, fmt.Errorf("unknown packet %v", hdr.PacketID)
+		}
+		pk := pkFunc()
+		pk.Marshal(proto.NewReader(buf, 0, false))
+		return proto.ConvertToLatest(pk, &minecraft.Conn{})[0], nil
+	}
+
+	if err := write(&packet.RequestNetworkSettings{ClientProtocol: proto.ID()}); err != nil {
+		return "", err
+	}
+	pk, err := read()
+	if err != nil {
+		return "", err
+	}
+	settings, ok := pk.(*packet.NetworkSettings)
+	if !ok {
+		return "", fmt.Errorf("expected NetworkSettings, got %T", pk)
+	}
+	compression, _ := packet.CompressionByID(settings.CompressionAlgorithm)
+	enc.EnableCompression(compression, proto.ID() <= 630)
+	if proto.ID() <= 630 {
+		dec.SetCompression(compression)
+	} else {
+		dec.EnableCompression()
+	}
+
+	if err := write(&packet.Login{ClientProtocol: proto.ID()}); err != nil {
+		return "", err
+	}
+	if pk, err = read(); err != nil {
+		return "", err
+	}
+	disconnect, ok := pk.(*packet.Disconnect)
+	if !ok {
+		return "", fmt.Errorf("expected Disconnect, got %T", pk)
+	}
+	return disconnect.Message, nil
+}
@@ -0,0 +1,161 @@
+package vers
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/oomph-ac/mv/multiversion/capability"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/sandertv/gophertunnel/minecraft/resource"
+)
+
+// WithAlternativePack registers a resource pack that is sent instead of the resource pack with the UUID passed
+// to clients with a version older than the min_engine_version in the manifest of that pack. The alternative is
+// only sent to clients that support its own min_engine_version. If several alternatives are registered for the
+// same resource pack, clients are sent the newest one that they support.
+func WithAlternativePack(uuid string, pack *resource.Pack) Option {
+	return func(o *options) {
+		if o.alternativePacks == nil {
+			o.alternativePacks = make(map[string][]*resource.Pack)
+		}
+		o.alternativePacks[uuid] = append(o.alternativePacks[uuid], pack)
+	}
+}
+
+// packProtocols returns the protocols passed with the protocols of clients that may not load all resource packs
+// wrapped, so that they are only sent the resource packs selected for their version. The latest protocol is
+// added if its clients may not load all of them either. The resource packs the listener must hold, including
+// the alternatives, are returned too.
+func packProtocols(protocols []minecraft.Protocol, packs []*resource.Pack, alternatives map[string][]*resource.Pack) ([]minecraft.Protocol, []*resource.Pack) {
+	// Alternatives directly follow the resource pack they replace, so that they take its place in the pack
+	// stack, which decides the priority of the resource packs.
+	all := make([]*resource.Pack, 0, len(packs))
+	for _, pack := range packs {
+		all = append(all, pack)
+		for _, alternative := range alternatives[pack.UUID()] {
+			if !slices.Contains(all, alternative) {
+				all = append(all, alternative)
+			}
+		}
+	}
+
+	wrapped := make([]minecraft.Protocol, 0, len(protocols)+1)
+	for _, proto := range append(slices.Clone(protocols), minecraft.DefaultProtocol) {
+		selected := selectPacks(packs, alternatives, proto.Ver())
+		excluded := make(map[string]struct{})
+		for _, pack := range all {
+			if !slices.Contains(selected, pack) {
+				excluded[packKey(pack.UUID(), pack.Version())] = struct{}{}
+			}
+		}
+		if len(excluded) != 0 {
+			wrapped = append(wrapped, packProtocol{Protocol: proto, excluded: excluded})
+		} else if proto != minecraft.DefaultProtocol {
+			wrapped = append(wrapped, proto)
+		}
+	}
+	return wrapped, all
+}
+
+// selectPacks returns the resource packs that a client using the version passed is sent. Resource packs that
+// the version does not support are replaced with the newest alternative that it supports, or left out if
+// there is none.
+func selectPacks(packs []*resource.Pack, alternatives map[string][]*resource.Pack, version string) []*resource.Pack {
+	selected := make([]*resource.Pack, 0, len(packs))
+	for _, pack := range packs {
+		if supportsPack(pack, version) {
+			selected = append(selected, pack)
+			continue
+		}
+		var newest *resource.Pack
+		for _, alternative := range alternatives[pack.UUID()] {
+			if supportsPack(alternative, version) && (newest == nil || compareVersions(minEngineVersion(alternative), minEngineVersion(newest)) > 0) {
+				newest = alternative
+			}
+		}
+		if newest != nil {
+			selected = append(selected, newest)
+		}
+	}
+	return selected
+}
+
+// supportsPack checks if a client using the version passed is able to load the resource pack passed, based on
+// the min_engine_version in its manifest.
+func supportsPack(pack *resource.Pack, version string) bool {
+	return compareVersions(minEngineVersion(pack), version) <= 0
+}
+
+// minEngineVersion returns the min_engine_version in the manifest of the resource pack passed, such as
+// "1.20.60".
+func minEngineVersion(pack *resource.Pack) string {
+	v := pack.Manifest().Header.MinimumGameVersion
+	return fmt.Sprintf("%v.%v.%v", v[0], v[1], v[2])
+}
+
+// packKey returns the key of a resource pack with the UUID and version passed, in the format that the client
+// uses to refer to it.
+func packKey(uuid, version string) string {
+	return uuid + "_" + version
+}
+
+// packProtocol is a minecraft.Protocol that leaves the resource packs that are not selected for its version out
+// of the ResourcePacksInfo and ResourcePackStack packets sent to its clients.
+type packProtocol struct {
+	minecraft.Protocol
+	// excluded holds the keys of the resource packs left out, as returned by packKey.
+	excluded map[string]struct{}
+}
+
+// Unwrap returns the protocol wrapped, which is the protocol passed when listening.
+func (p packProtocol) Unwrap() minecraft.Protocol {
+	return p.Protocol
+}
+
+// Capabilities returns the capability.Set of the protocol wrapped.
+func (p packProtocol) Capabilities() capability.Set {
+	return Capabilities(p.Protocol)
+}
+
+func (p packProtocol) ConvertFromLatest(pk packet.Packet, conn *minecraft.Conn) []packet.Packet {
+	switch pk := pk.(type) {
+	case *packet.ResourcePacksInfo:
+		pk.TexturePacks = slices.DeleteFunc(pk.TexturePacks, func(pack protocol.TexturePackInfo) bool {
+			return p.isExcluded(pack.UUID, pack.Version)
+		})
+		pk.BehaviourPacks = slices.DeleteFunc(pk.BehaviourPacks, func(pack protocol.BehaviourPackInfo) bool {
+			return p.isExcluded(pack.UUID, pack.Version)
+		})
+		pk.PackURLs = slices.DeleteFunc(pk.PackURLs, func(url protocol.PackURL) bool {
+			_, ok := p.excluded[url.UUIDVersion]
+			return ok
+		})
+		pk.HasScripts = slices.ContainsFunc(pk.BehaviourPacks, func(pack protocol.BehaviourPackInfo) bool {
+			return pack.HasScripts
+		})
+	case *packet.ResourcePackStack:
+		isExcluded := func(pack protocol.StackResourcePack) bool {
+			return p.isExcluded(pack.UUID, pack.Version)
+		}
+		pk.TexturePacks = slices.DeleteFunc(pk.TexturePacks, isExcluded)
+		pk.BehaviourPacks = slices.DeleteFunc(pk.BehaviourPacks, isExcluded)
+	}
+	return p.Protocol.ConvertFromLatest(pk, conn)
+}
+
+// unwrapProtocol returns the protocol passed when listening for the protocol of a connection, which may be
+// wrapped to leave out resource packs.
+func unwrapProtocol(proto minecraft.Protocol) minecraft.Protocol {
+	if p, ok := proto.(interface{ Unwrap() minecraft.Protocol }); ok {
+		return p.Unwrap()
+	}
+	return proto
+}
+
+// isExcluded checks if the resource pack with the UUID and version passed is left out for the protocol.
+func (p packProtocol) isExcluded(uuid, version string) bool {
+	_, ok := p.excluded[packKey(uuid, version)]
+	return ok
+}
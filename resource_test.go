@@ -0,0 +1,170 @@
+package vers
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/df-mc/dragonfly/server/event"
+	"github.com/df-mc/dragonfly/server/player"
+	"github.com/df-mc/dragonfly/server/player/skin"
+	"github.com/df-mc/dragonfly/server/session"
+	"github.com/go-gl/mathgl/mgl64"
+	"github.com/oomph-ac/mv/multiversion/mv589"
+	"github.com/oomph-ac/mv/multiversion/mv662"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/login"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/sandertv/gophertunnel/minecraft/resource"
+)
+
+// TestPackProtocols tests that clients are only sent the resource packs that their version supports, or the
+// newest alternative that it supports instead.
+func TestPackProtocols(t *testing.T) {
+	modern := testPack(t, "a", [3]int{1, 0, 0}, [3]int{1, 20, 60})
+	legacy := testPack(t, "a", [3]int{0, 1, 0}, [3]int{1, 19, 0})
+	older := testPack(t, "a", [3]int{0, 0, 1}, [3]int{1, 16, 0})
+	common := testPack(t, "b", [3]int{1, 0, 0}, [3]int{1, 16, 0})
+
+	protocols, packs := packProtocols([]minecraft.Protocol{mv589.Protocol{}, mv662.Protocol{}}, []*resource.Pack{modern, common}, map[string][]*resource.Pack{
+		"a": {older, legacy},
+	})
+	if len(packs) != 4 {
+		t.Fatalf("expected the listener to hold 4 resource packs, got %v", len(packs))
+	}
+	// The 1.20.70 clients and the latest clients are sent the same packs, but not the alternatives.
+	if len(protocols) != 3 {
+		t.Fatalf("expected 3 protocols, got %v", len(protocols))
+	}
+
+	tests := []struct {
+		proto minecraft.Protocol
+		want  []string
+	}{
+		{proto: protocols[0], want: []string{"a_0.1.0", "b_1.0.0"}},
+		{proto: protocols[1], want: []string{"a_1.0.0", "b_1.0.0"}},
+		{proto: protocols[2], want: []string{"a_1.0.0", "b_1.0.0"}},
+	}
+	for _, test := range tests {
+		info := &packet.ResourcePacksInfo{}
+		for _, pack := range packs {
+			info.TexturePacks = append(info.TexturePacks, protocol.TexturePackInfo{UUID: pack.UUID(), Version: pack.Version()})
+		}
+		var got []string
+		conn := &minecraft.Conn{}
+		for _, pk := range test.proto.ConvertFromLatest(info, conn) {
+			// Older protocols have their own ResourcePacksInfo packet, so it is converted back first.
+			for _, pk := range test.proto.ConvertToLatest(pk, conn) {
+				for _, pack := range pk.(*packet.ResourcePacksInfo).TexturePacks {
+					got = append(got, packKey(pack.UUID, pack.Version))
+				}
+			}
+		}
+		if len(got) != len(test.want) {
+			t.Errorf("%v: expected resource packs %v, got %v", test.proto.Ver(), test.want, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("%v: expected resource packs %v, got %v", test.proto.Ver(), test.want, got)
+				break
+			}
+		}
+	}
+}
+
+// protocolHandler is a Handler that records the protocol that a connection joined with.
+type protocolHandler struct {
+	NopHandler
+	protocols chan minecraft.Protocol
+}
+
+func (h protocolHandler) HandleJoin(_ *event.Context, _ session.Conn, proto minecraft.Protocol, _ *string) {
+	h.protocols <- proto
+}
+
+// TestPackProtocolsUnwrap tests that the protocol of a client that is left out of resource packs is the one
+// passed when listening, rather than the protocol wrapping it.
+func TestPackProtocolsUnwrap(t *testing.T) {
+	protocols, _ := packProtocols([]minecraft.Protocol{mv589.Protocol{}}, []*resource.Pack{
+		testPack(t, "a", [3]int{1, 0, 0}, [3]int{1, 20, 60}),
+	}, nil)
+	if _, ok := protocols[0].(packProtocol); !ok {
+		t.Fatalf("expected the 1.20.0 protocol to be wrapped, got %T", protocols[0])
+	}
+	l, err := minecraft.ListenConfig{AuthenticationDisabled: true, AcceptedProtocols: protocols}.Listen(LoopbackNetwork, "127.0.0.1:19142")
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := New()
+	h := protocolHandler{protocols: make(chan minecraft.Protocol, 1)}
+	v.Handle(h)
+	ln := newListener(l, v)
+	t.Cleanup(func() {
+		_ = ln.Close()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	t.Cleanup(cancel)
+	p := player.New("Test", skin.Skin{}, mgl64.Vec3{})
+	go func() {
+		_, _ = minecraft.Dialer{
+			IdentityData: login.IdentityData{DisplayName: "Test", Identity: p.UUID().String()},
+			Protocol:     mv589.Protocol{},
+		}.DialContext(ctx, LoopbackNetwork, "127.0.0.1:19142")
+	}()
+
+	accepted := make(chan session.Conn, 1)
+	go func() {
+		if c, err := ln.Accept(); err == nil {
+			accepted <- c
+		}
+	}()
+	select {
+	case c := <-accepted:
+		defer c.Close()
+		if proto := <-h.protocols; proto != (mv589.Protocol{}) {
+			t.Errorf("expected HandleJoin to be passed mv589.Protocol, got %T", proto)
+		}
+		if proto, ok := ConnProtocol(c); !ok || proto != (mv589.Protocol{}) {
+			t.Errorf("expected ConnProtocol to return mv589.Protocol, got %T", proto)
+		}
+		if proto, ok := PlayerProtocol(p); !ok || proto != (mv589.Protocol{}) {
+			t.Errorf("expected PlayerProtocol to return mv589.Protocol, got %T", proto)
+		}
+	case <-ctx.Done():
+		t.Fatalf("the connection was not accepted")
+	}
+}
+
+// testPack creates a resource pack with the UUID, version and min_engine_version passed.
+func testPack(t *testing.T, uuid string, version, minEngineVersion [3]int) *resource.Pack {
+	manifest, err := json.Marshal(resource.Manifest{FormatVersion: 2, Header: resource.Header{
+		Name:               uuid,
+		UUID:               uuid,
+		Version:            version,
+		MinimumGameVersion: minEngineVersion,
+	}, Modules: []resource.Module{{UUID: uuid + "-resources", Type: "resources", Version: version}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := bytes.NewBuffer(nil)
+	w := zip.NewWriter(buf)
+	f, err := w.Create("manifest.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = f.Write(manifest)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	pack, err := resource.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pack
+}
@@ -0,0 +1,249 @@
+package vers
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/sandertv/go-raknet"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// StatusProvider is a minecraft.ServerStatusProvider that is aware of the protocols accepted by a Vers listener.
+// It advertises the range of versions supported in the server list, may show a different MOTD to each version
+// and may answer each pinging client with its own version, if it is accepted.
+type StatusProvider struct {
+	name      string
+	perClient bool
+
+	mu        sync.RWMutex
+	protocols []minecraft.Protocol
+	motd      map[int32]string
+	clients   map[netip.Addr]int32
+}
+
+// maxStatusClients is the maximum amount of client addresses a StatusProvider remembers the protocol of.
+const maxStatusClients = 8192
+
+// statusNetworkID is the ID of the network that listeners with a StatusProvider listen on. It is registered
+// once and looks up the StatusProvider of each address listened on.
+const statusNetworkID = "vers-status"
+
+var (
+	// statusMu guards statusProviders.
+	statusMu sync.Mutex
+	// statusProviders holds the StatusProvider of every address listened on using the status network.
+	statusProviders = map[string]*StatusProvider{}
+)
+
+func init() {
+	minecraft.RegisterNetwork(statusNetworkID, statusNetwork{})
+}
+
+// NewStatusProvider creates a StatusProvider that displays the server name passed. If perClient is true, clients
+// that previously connected with an accepted protocol are answered with their own version when pinging the
+// server, so that they are not shown the server as outdated or incompatible.
+func NewStatusProvider(name string, perClient bool) *StatusProvider {
+	return &StatusProvider{
+		name:      name,
+		perClient: perClient,
+		motd:      make(map[int32]string),
+		clients:   make(map[netip.Addr]int32),
+	}
+}
+
+// listenOn makes the StatusProvider answer the pings of listeners on the status network with the address
+// passed.
+func (s *StatusProvider) listenOn(address string) {
+	statusMu.Lock()
+	defer statusMu.Unlock()
+	statusProviders[address] = s
+}
+
+// SetMOTD sets the MOTD shown to clients with the protocol ID passed. The server list does not tell the version
+// of a client pinging the server, so the MOTD is shown to clients from an address that previously connected
+// with the protocol. The server name passed to NewStatusProvider is shown to other clients.
+func (s *StatusProvider) SetMOTD(protocol int32, motd string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.motd[protocol] = motd
+}
+
+// ServerStatus ...
+func (s *StatusProvider) ServerStatus(playerCount, maxPlayers int) minecraft.ServerStatus {
+	return minecraft.ServerStatus{
+		ServerName:  s.name,
+		PlayerCount: playerCount,
+		MaxPlayers:  maxPlayers,
+	}
+}
+
+// VersionRange returns the lowest and highest version accepted, such as "1.20.0" and "1.20.80".
+func (s *StatusProvider) VersionRange() (lowest, highest string) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	lowest, highest = protocol.CurrentVersion, protocol.CurrentVersion
+	lowestID, highestID := int32(protocol.CurrentProtocol), int32(protocol.CurrentProtocol)
+	for _, p := range s.protocols {
+		if p.ID() < lowestID {
+			lowest, lowestID = p.Ver(), p.ID()
+		}
+		if p.ID() > highestID {
+			highest, highestID = p.Ver(), p.ID()
+		}
+	}
+	return lowest, highest
+}
+
+// setProtocols sets the protocols accepted by the listener that uses the StatusProvider.
+func (s *StatusProvider) setProtocols(protocols []minecraft.Protocol) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.protocols = slices.Clone(protocols)
+}
+
+// packetFunc records the protocol of clients requesting network settings, so that they may be answered with
+// their own MOTD, and their own version if the StatusProvider answers per client, when pinging afterwards.
+func (s *StatusProvider) packetFunc(header packet.Header, payload []byte, src, _ net.Addr) {
+	if header.PacketID != packet.IDRequestNetworkSettings || len(payload) < 4 {
+		return
+	}
+	addr, ok := addrIP(src)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.clients[addr]; !ok && len(s.clients) >= maxStatusClients {
+		for a := range s.clients {
+			delete(s.clients, a)
+			break
+		}
+	}
+	s.clients[addr] = int32(binary.BigEndian.Uint32(payload))
+}
+
+// pong rewrites the pong data passed for a ping coming from the address passed. The MOTD in the data is
+// replaced with that of the protocol of the client if known. The protocol and version are replaced with those
+// of the client if known and the StatusProvider answers per client, or with the latest protocol and the range
+// of versions otherwise.
+func (s *StatusProvider) pong(data []byte, addr net.Addr) []byte {
+	frag := strings.Split(string(data), ";")
+	if len(frag) < 4 {
+		return data
+	}
+	lowest, highest := s.VersionRange()
+	frag[2], frag[3] = strconv.Itoa(protocol.CurrentProtocol), lowest+"-"+highest
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if ip, ok := addrIP(addr); ok {
+		if id, ok := s.clients[ip]; ok {
+			if ver, ok := s.version(id); ok {
+				if s.perClient {
+					frag[2], frag[3] = strconv.Itoa(int(id)), ver
+				}
+				if motd, ok := s.motd[id]; ok {
+					frag[1] = motd
+				}
+			}
+		}
+	}
+	return []byte(strings.Join(frag, ";"))
+}
+
+// version returns the version of the accepted protocol with the ID passed. s.mu must be held when calling
+// version.
+func (s *StatusProvider) version(id int32) (string, bool) {
+	if id == protocol.CurrentProtocol {
+		return protocol.CurrentVersion, true
+	}
+	for _, p := range s.protocols {
+		if p.ID() == id {
+			return p.Ver(), true
+		}
+	}
+	return "", false
+}
+
+// addrIP returns the IP of the network address passed.
+func addrIP(addr net.Addr) (netip.Addr, bool) {
+	if udp, ok := addr.(*net.UDPAddr); ok {
+		ip, ok := netip.AddrFromSlice(udp.IP)
+		return ip.Unmap(), ok
+	}
+	ap, err := netip.ParseAddrPort(addr.String())
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return ap.Addr().Unmap(), true
+}
+
+// statusNetwork is a minecraft.Network that uses RakNet, but rewrites the pong data sent to pinging clients
+// using the StatusProvider of the address listened on.
+type statusNetwork struct {
+	minecraft.RakNet
+}
+
+// Listen ...
+func (n statusNetwork) Listen(address string) (minecraft.NetworkListener, error) {
+	statusMu.Lock()
+	s, ok := statusProviders[address]
+	statusMu.Unlock()
+	if !ok {
+		return n.RakNet.Listen(address)
+	}
+	return raknet.ListenConfig{UpstreamPacketListener: statusPacketListener{s: s}}.Listen(address)
+}
+
+// statusPacketListener is a raknet.UpstreamPacketListener that listens using statusConns.
+type statusPacketListener struct {
+	s *StatusProvider
+}
+
+// ListenPacket ...
+func (l statusPacketListener) ListenPacket(network, address string) (net.PacketConn, error) {
+	conn, err := net.ListenPacket(network, address)
+	if err != nil {
+		return nil, err
+	}
+	return statusConn{PacketConn: conn, s: l.s}, nil
+}
+
+// statusConn is a net.PacketConn that rewrites unconnected pongs written to it.
+type statusConn struct {
+	net.PacketConn
+	s *StatusProvider
+}
+
+const (
+	// idUnconnectedPong is the ID of a RakNet unconnected pong.
+	idUnconnectedPong = 0x1c
+	// unconnectedPongHeader is the size of an unconnected pong before its data: The ID, the send timestamp, the
+	// server GUID and the magic.
+	unconnectedPongHeader = 1 + 8 + 8 + 16
+)
+
+// WriteTo ...
+func (c statusConn) WriteTo(b []byte, addr net.Addr) (int, error) {
+	if len(b) < unconnectedPongHeader+2 || b[0] != idUnconnectedPong {
+		return c.PacketConn.WriteTo(b, addr)
+	}
+	data := c.s.pong(b[unconnectedPongHeader+2:], addr)
+
+	buf := bytes.NewBuffer(make([]byte, 0, unconnectedPongHeader+2+len(data)))
+	buf.Write(b[:unconnectedPongHeader])
+	_ = binary.Write(buf, binary.BigEndian, int16(len(data)))
+	buf.Write(data)
+	if _, err := c.PacketConn.WriteTo(buf.Bytes(), addr); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
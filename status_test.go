@@ -0,0 +1,61 @@
+package vers
+
+import (
+	"encoding/binary"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/oomph-ac/mv/multiversion/mv589"
+	"github.com/oomph-ac/mv/multiversion/mv662"
+	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+)
+
+// testPong is the pong data of a server as written by gophertunnel.
+var testPong = "MCPE;Server;" + strconv.Itoa(protocol.CurrentProtocol) + ";" + protocol.CurrentVersion + ";0;10;1;Server;Creative;1;19132;19132;"
+
+// TestStatusVersionRange tests that the version range spans the accepted protocols and the latest protocol.
+func TestStatusVersionRange(t *testing.T) {
+	s := NewStatusProvider("Server", false)
+	if lowest, highest := s.VersionRange(); lowest != protocol.CurrentVersion || highest != protocol.CurrentVersion {
+		t.Errorf("expected only the latest version without protocols, got %v-%v", lowest, highest)
+	}
+	s.setProtocols([]minecraft.Protocol{mv662.Protocol{}, mv589.Protocol{}})
+	if lowest, highest := s.VersionRange(); lowest != "1.20.0" || highest != protocol.CurrentVersion {
+		t.Errorf("expected 1.20.0-%v, got %v-%v", protocol.CurrentVersion, lowest, highest)
+	}
+}
+
+// TestStatusPong tests that the pong data holds the latest protocol and the range of versions for unknown
+// clients, and the MOTD and, if the StatusProvider answers per client, the version of known clients.
+func TestStatusPong(t *testing.T) {
+	known, unknown := &net.UDPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 1234}, &net.UDPAddr{IP: net.IPv4(10, 0, 0, 2), Port: 1234}
+	latestFrag := []string{"Server", strconv.Itoa(protocol.CurrentProtocol), "1.20.0-" + protocol.CurrentVersion}
+
+	tests := []struct {
+		perClient bool
+		addr      net.Addr
+		want      []string
+	}{
+		{perClient: false, addr: unknown, want: latestFrag},
+		{perClient: false, addr: known, want: []string{"Old", strconv.Itoa(protocol.CurrentProtocol), "1.20.0-" + protocol.CurrentVersion}},
+		{perClient: true, addr: unknown, want: latestFrag},
+		{perClient: true, addr: known, want: []string{"Old", "589", "1.20.0"}},
+	}
+	for _, test := range tests {
+		s := NewStatusProvider("Server", test.perClient)
+		s.setProtocols([]minecraft.Protocol{mv589.Protocol{}})
+		s.SetMOTD(589, "Old")
+
+		payload := binary.BigEndian.AppendUint32(nil, 589)
+		s.packetFunc(packet.Header{PacketID: packet.IDRequestNetworkSettings}, payload, known, nil)
+
+		frag := strings.Split(string(s.pong([]byte(testPong), test.addr)), ";")
+		if got := []string{frag[1], frag[2], frag[3]}; strings.Join(got, ";") != strings.Join(test.want, ";") {
+			t.Errorf("perClient = %v, %v: expected %v, got %v", test.perClient, test.addr, test.want, got)
+		}
+	}
+}
@@ -1,40 +1,181 @@
 package vers
 
 import (
+	"log"
+	"net"
+	"slices"
+	"sync"
+
 	"github.com/df-mc/dragonfly/server"
 	"github.com/sandertv/gophertunnel/minecraft"
+	"github.com/sandertv/gophertunnel/minecraft/protocol/packet"
+	"github.com/sandertv/gophertunnel/minecraft/resource"
+	"github.com/sirupsen/logrus"
 )
 
 // Vers is an instance for binding a multi-version Dragonfly server.
 type Vers struct {
-	addr string
+	addrs []string
+
+	hMu sync.RWMutex
+	h   Handler
+
+	r *rejecter
 }
 
-// New creates a new Vers instance.
-func New(localAddr string) *Vers {
-	return &Vers{
-		addr: localAddr,
+// New creates a new Vers instance. A listener is created for each of the addresses passed once Listen is
+// called, for example to listen on both an IPv4 and an IPv6 address.
+func New(addrs ...string) *Vers {
+	v := &Vers{
+		addrs: addrs,
+		h:     NopHandler{},
 	}
+	v.r = &rejecter{v: v, count: make(map[int32]uint64)}
+	return v
 }
 
-// Listen listens for incoming connections on the address.
-func (v *Vers) Listen(conf *server.Config, name string, protocols []minecraft.Protocol, requirePacks bool) {
-	conf.Listeners = nil
-	conf.Listeners = append(conf.Listeners, func(_ server.Config) (server.Listener, error) {
-		l, err := minecraft.ListenConfig{
-			StatusProvider:       minecraft.NewStatusProvider(name),
-			ResourcePacks:        conf.Resources,
-			TexturePacksRequired: requirePacks,
-			AcceptedProtocols:    protocols,
-		}.Listen("raknet", v.addr)
-		if err != nil {
-			return nil, err
-		}
+// Handle changes the handler of the Vers instance to the Handler passed. If nil is passed, the NopHandler is
+// used instead.
+func (v *Vers) Handle(h Handler) {
+	if h == nil {
+		h = NopHandler{}
+	}
+	v.hMu.Lock()
+	defer v.hMu.Unlock()
+	v.h = h
+}
+
+// handler returns the current Handler of the Vers instance.
+func (v *Vers) handler() Handler {
+	v.hMu.RLock()
+	defer v.hMu.RUnlock()
+	return v.h
+}
+
+// Option is an option that may be passed to Vers.Listen to change the way its listeners are created.
+type Option func(o *options)
+
+// options holds the options passed to Vers.Listen.
+type options struct {
+	// conf is the base minecraft.ListenConfig that the multi-version settings are merged into.
+	conf minecraft.ListenConfig
+	// network is the network listened on, "raknet" by default.
+	network string
+	// keepListeners specifies if the listeners already present in the server.Config are kept.
+	keepListeners bool
+	// rejectMessage is the message shown to clients with a protocol that is not accepted.
+	rejectMessage string
+	// preload specifies if the mappings of the protocols are loaded when Listen is called.
+	preload bool
+	// alternativePacks holds the resource packs registered using WithAlternativePack, indexed by the UUID of
+	// the resource pack that they replace.
+	alternativePacks map[string][]*resource.Pack
+}
+
+// WithListenConfig sets the base minecraft.ListenConfig of the listeners. Fields such as authentication,
+// compression, packet limits, the maximum player count, the flush rate and the error logger are used as is. The
+// resource packs, accepted protocols and texture pack requirement passed to Vers.Listen are added to those of
+// the config.
+func WithListenConfig(conf minecraft.ListenConfig) Option {
+	return func(o *options) {
+		o.conf = conf
+	}
+}
+
+// WithNetwork sets the network the listeners listen on. It must be registered using minecraft.RegisterNetwork.
+func WithNetwork(network string) Option {
+	return func(o *options) {
+		o.network = network
+	}
+}
 
-		conf.Log.Infof("Server running on %v.", l.Addr())
+// KeepListeners keeps the listeners already present in the server.Config, instead of replacing them with the
+// listeners of the Vers instance.
+func KeepListeners() Option {
+	return func(o *options) {
+		o.keepListeners = true
+	}
+}
 
-		return listener{
-			Listener: l,
-		}, nil
-	})
+// PreloadMappings loads the mappings of the protocols passed to Vers.Listen when it is called, instead of when the
+// first client using each of them joins. Mappings of protocols that are not passed are never loaded.
+func PreloadMappings() Option {
+	return func(o *options) {
+		o.preload = true
+	}
+}
+
+// Listen listens for incoming connections on the addresses of the Vers instance. Unless a different
+// StatusProvider is set through WithListenConfig, a StatusProvider displaying the name passed is used.
+func (v *Vers) Listen(conf *server.Config, name string, protocols []minecraft.Protocol, requirePacks bool, opts ...Option) {
+	o := options{network: "raknet", rejectMessage: defaultRejectMessage}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.conf.StatusProvider == nil {
+		o.conf.StatusProvider = NewStatusProvider(name, false)
+	}
+	if o.preload {
+		Preload(protocols...)
+	}
+
+	if !o.keepListeners {
+		conf.Listeners = nil
+	}
+	for _, addr := range v.addrs {
+		addr := addr
+		conf.Listeners = append(conf.Listeners, func(c server.Config) (server.Listener, error) {
+			cfg, network := v.listenConfig(o, c, protocols, requirePacks)
+			if s, ok := cfg.StatusProvider.(*StatusProvider); ok && network == statusNetworkID {
+				s.listenOn(addr)
+			}
+			l, err := cfg.Listen(network, addr)
+			if err != nil {
+				return nil, err
+			}
+
+			c.Log.Infof("Server running on %v.", l.Addr())
+
+			return newListener(l, v), nil
+		})
+	}
+}
+
+// listenConfig merges the multi-version settings and those of the server.Config passed into the base
+// minecraft.ListenConfig of the options. It returns the config and the network to listen on.
+func (v *Vers) listenConfig(o options, c server.Config, protocols []minecraft.Protocol, requirePacks bool) (minecraft.ListenConfig, string) {
+	conf, network := o.conf, o.network
+	if conf.MaximumPlayers == 0 {
+		conf.MaximumPlayers = c.MaxPlayers
+	}
+	if conf.ErrorLog == nil {
+		if l, ok := c.Log.(*logrus.Logger); ok {
+			conf.ErrorLog = log.New(l.WithField("src", "gophertunnel").WriterLevel(logrus.DebugLevel), "", 0)
+		}
+	}
+	conf.AuthenticationDisabled = conf.AuthenticationDisabled || c.AuthDisabled
+	conf.TexturePacksRequired = conf.TexturePacksRequired || requirePacks
+	conf.ResourcePacks = append(slices.Clone(conf.ResourcePacks), c.Resources...)
+	conf.AcceptedProtocols = append(slices.Clone(conf.AcceptedProtocols), protocols...)
+	// Clients are only sent the resource packs that their version is able to load.
+	conf.AcceptedProtocols, conf.ResourcePacks = packProtocols(conf.AcceptedProtocols, conf.ResourcePacks, o.alternativePacks)
+
+	if s, ok := conf.StatusProvider.(*StatusProvider); ok {
+		s.setProtocols(conf.AcceptedProtocols)
+		if f := conf.PacketFunc; f != nil {
+			conf.PacketFunc = func(header packet.Header, payload []byte, src, dst net.Addr) {
+				s.packetFunc(header, payload, src, dst)
+				f(header, payload, src, dst)
+			}
+		} else {
+			conf.PacketFunc = s.packetFunc
+		}
+		if network == "raknet" {
+			network = statusNetworkID
+		}
+	}
+	// Protocols that are not accepted are added last, so that clients using them are shown a message instead
+	// of the generic message of the game.
+	conf.AcceptedProtocols = append(conf.AcceptedProtocols, v.r.protocols(conf.AcceptedProtocols, o.rejectMessage)...)
+	return conf, network
 }
@@ -13,8 +13,12 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
-// TestVers tests the Vers multi-version support for Dragonfly.
+// TestVers tests the Vers multi-version support for Dragonfly. It starts a server on :6900 that real clients
+// may join and never returns, so it only runs if the VERS_LISTEN environment variable is set.
 func TestVers(t *testing.T) {
+	if os.Getenv("VERS_LISTEN") == "" {
+		t.Skip("VERS_LISTEN not set")
+	}
 	log := logrus.New()
 	log.Formatter = &logrus.TextFormatter{ForceColors: true}
 	log.Level = logrus.DebugLevel